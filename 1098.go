@@ -6,30 +6,24 @@ import (
 )
 
 // Submit1098Request represents the JSON structure for submitting 1098 forms
-type Submit1098Request struct {
-	TaxYear string     `json:"taxYear"`
-	Items   []Item1098 `json:"items"`
-}
+type Submit1098Request = SubmitRequest[Form1098]
 
-// Item represents a single payer and their associated forms
-type Item1098 struct {
-	PayerInfo PayerInfo  `json:"payerInfo"` //Payer Info is the seller/lender of the property
-	Forms     []Form1098 `json:"forms"`
-}
+// Item1098 represents a single payer and their associated 1098 forms
+type Item1098 = Item[Form1098]
 
 // Form1098 represents the details of a single 1098 form
 type Form1098 struct {
-	RecipientInfo       RecipientInfo `json:"recipientInfo"`      //Recipient Info is the buyer/borrower of the property
-	TaxYear             string        `json:"taxYear"`            //Tax Year is the year for which the form is being filed
-	AcctNo              string        `json:"acctNo"`             //Account Number is required if you have multiple accounts for a payer/borrower for whom you are filing more than one Form 1098
-	MortgageInterest    float64       `json:"mortgageInterest"`   //Mortgage Interest is the amount of interest received from the borrower during the tax year
-	PrincipalResidence  float64       `json:"principalResidence"` //Principal Residence is the points paid by the borrower for the residence
-	OverpaidInterest    float64       `json:"overpaidInterest"`   //Overpaid Interest is the amount of interest received from the borrower that was refunded or credited during the tax year
-	MortgagePremiums    float64       `json:"mortgagePremiums"`   //Mortgage Premiums is not used currently.
-	MortgagePrincipal   float64       `json:"mortgagePrincipal"`  //Mortgage Principal is amount of principal as the start of the calendar year
-	MortgageDate        string        `json:"mortgageDate"`       //Mortgage Date is the date the mortgage was originated
-	IsAddressSame       bool          `json:"isAddressSame"`      //Is Address Same is used to indicate if the property address is the same as the recipient address
-	PropertyAddress     string        `json:"propertyAddress"`    //Property Address is the address of the property for which the form is being filed
+	RecipientInfo       RecipientInfo `json:"recipientInfo"`         //Recipient Info is the buyer/borrower of the property
+	TaxYear             TaxYear       `json:"taxYear"`               //Tax Year is the year for which the form is being filed
+	AcctNo              string        `json:"acctNo"`                //Account Number is required if you have multiple accounts for a payer/borrower for whom you are filing more than one Form 1098
+	MortgageInterest    Money         `json:"mortgageInterest"`      //Mortgage Interest is the amount of interest received from the borrower during the tax year
+	PrincipalResidence  Money         `json:"principalResidence"`    //Principal Residence is the points paid by the borrower for the residence
+	OverpaidInterest    Money         `json:"overpaidInterest"`      //Overpaid Interest is the amount of interest received from the borrower that was refunded or credited during the tax year
+	MortgagePremiums    Money         `json:"mortgagePremiums"`      //Mortgage Premiums is not used currently.
+	MortgagePrincipal   Money         `json:"mortgagePrincipal"`     //Mortgage Principal is amount of principal as the start of the calendar year
+	MortgageDate        Date          `json:"mortgageDate,omitzero"` //Mortgage Date is the date the mortgage was originated
+	IsAddressSame       bool          `json:"isAddressSame"`         //Is Address Same is used to indicate if the property address is the same as the recipient address
+	PropertyAddress     string        `json:"propertyAddress"`       //Property Address is the address of the property for which the form is being filed
 	PropertyDescription string        `json:"propertyDescription,omitempty"`
 	USPSMail            bool          `json:"uspsMail"`        //USPS Mail is used to indicate if the form should be mailed to the payer
 	TINCheck            bool          `json:"tinCheck"`        //TIN Check is used to indicate if the TIN should be checked
@@ -37,8 +31,9 @@ type Form1098 struct {
 	CorrectedReturn     bool          `json:"correctedReturn"` //Corrected Return is used to indicate if the form is a corrected return
 }
 
-// Submit1098Response represents the response for the Submit 1098 API
-type Submit1098Response struct {
+// FormResponse represents the response from the validate and import APIs of
+// every form type
+type FormResponse struct {
 	Result             []SubmissionResult `json:"result"`
 	TotalCount         int                `json:"totalCount"`
 	ValidationErrors   []ValidationError  `json:"validationErrors"`
@@ -48,62 +43,65 @@ type Submit1098Response struct {
 	IsError            bool               `json:"isError"`
 }
 
+// Submit1098Response represents the response for the Submit 1098 API
+type Submit1098Response = FormResponse
+
 // SubmissionResult represents individual submission results
 type SubmissionResult struct {
 	ID         int  `json:"id"`
 	IsInserted bool `json:"isInserted"`
 }
 
-func (t *tax1099Impl) Validate1098(ctx context.Context, payload Submit1098Request) (Submit1098Response, error) {
-	const op = "tax1099.validate_1098"
-
-	slog.InfoContext(ctx, "Submitting the 1098 form for validation...",
-		slog.String("component", component),
-		slog.String("op", op),
-	)
-
-	urlPart := "forms/1098/validate"
-	if t.isProduction() {
-		urlPart = "form/1098/validate"
-	}
-
-	var res Submit1098Response
-	if err := t.post(ctx, op, t.generateFullUrl(Url1098, urlPart), payload, &res); err != nil {
-		return res, err
-	}
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1098",
+		Segment: "1098",
+		UrlType: Url1098,
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging:    "https://apiforms.1099cloud.com/api/v1",
+			EnvironmentProduction: "https://form1098.tax1099.com/api/v1",
+		},
+	})
+}
 
-	slog.InfoContext(ctx, "Validation response",
-		slog.String("component", component),
-		slog.String("op", op),
-		slog.Any("response", res),
-	)
+func (Form1098) FormName() string           { return "1098" }
+func (f Form1098) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1098) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1098) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
 
-	return res, nil
+func (t *tax1099Impl) Validate1098(ctx context.Context, payload Submit1098Request) (Submit1098Response, error) {
+	return Validate(ctx, t, payload)
 }
 
 func (t *tax1099Impl) Import1098(ctx context.Context, payload Submit1098Request) (Submit1098Response, error) {
-	const op = "tax1099.import_1098"
+	return Import(ctx, t, payload)
+}
 
-	slog.InfoContext(ctx, "Submitting the 1098 form for import...",
-		slog.String("component", component),
-		slog.String("op", op),
-	)
+// Validate checks the form without calling the API: the recipient, that amounts
+// are not negative, that MortgageDate is a real date, that the property address is given
+// only when it differs from the recipient's, and the TaxYear format.
+func (f Form1098) Validate() []ValidationError {
+	errs := within("recipientInfo", f.RecipientInfo.Validate())
 
-	urlPart := "forms/importonly/1098"
-	if t.isProduction() {
-		urlPart = "form/importonly/1098"
-	}
+	var v validator
 
-	var res Submit1098Response
-	if err := t.post(ctx, op, t.generateFullUrl(Url1098, urlPart), payload, &res); err != nil {
-		return res, err
+	if f.TaxYear != "" && !f.TaxYear.IsValid() {
+		v.add("taxYear", "must be a four digit year")
 	}
 
-	slog.InfoContext(ctx, "Import response",
-		slog.String("component", component),
-		slog.String("op", op),
-		slog.Any("response", res),
-	)
+	v.nonNegative("mortgageInterest", f.MortgageInterest)
+	v.nonNegative("principalResidence", f.PrincipalResidence)
+	v.nonNegative("overpaidInterest", f.OverpaidInterest)
+	v.nonNegative("mortgagePremiums", f.MortgagePremiums)
+	v.nonNegative("mortgagePrincipal", f.MortgagePrincipal)
+	v.date("mortgageDate", f.MortgageDate)
+
+	switch {
+	case f.IsAddressSame && f.PropertyAddress != "":
+		v.add("propertyAddress", "must be empty when isAddressSame is set")
+	case !f.IsAddressSame && f.PropertyAddress == "" && f.PropertyDescription == "":
+		v.add("propertyAddress", "is required when isAddressSame is not set, unless propertyDescription is given")
+	}
 
-	return res, nil
+	return append(errs, v.errs...)
 }
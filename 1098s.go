@@ -1,22 +1,13 @@
 package tax1099
 
-import (
-	"context"
-	"log/slog"
-	"time"
-)
+import "context"
 
-type Submit1098sRequest struct {
-	TaxYear         string     `json:"taxYear"`
-	FormName        string     `json:"formName"`
-	ScheduledDate   time.Time  `json:"scheduledDate"`
-	IsCorrected     bool       `json:"isCorrected"`
-	CouponCode      string     `json:"couponCode"`
-	CardReferenceID string     `json:"cardReferenceId"`
-	Items           []Item1098 `json:"items"`
-}
+// Submit1098sRequest represents the JSON structure for submitting 1098 forms for filing
+type Submit1098sRequest = PaymentRequest[Form1098]
 
-type Submit1098sResponse struct {
+// PaymentResponse represents the response from the payment submit APIs of
+// every form type
+type PaymentResponse struct {
 	TraceIdentifier        string `json:"traceIdentifier,omitempty"`
 	Message                string `json:"message,omitempty"`
 	StatusCode             int    `json:"statusCode,omitempty"`
@@ -27,24 +18,8 @@ type Submit1098sResponse struct {
 	TotalCount             int    `json:"totalCount,omitempty"`
 }
 
-func (t *tax1099Impl) Submit1098s(ctx context.Context, payload Submit1098sRequest) (Submit1098sResponse, error) {
-	const op = "tax1099.submit_1098s"
-
-	slog.InfoContext(ctx, "Submitting the 1098 forms...",
-		slog.String("component", component),
-		slog.String("op", op),
-	)
+type Submit1098sResponse = PaymentResponse
 
-	var res Submit1098sResponse
-	if err := t.post(ctx, op, t.generateFullUrl(UrlPayment, "payment/forms/import/submit/1098"), payload, &res); err != nil {
-		return res, err
-	}
-
-	slog.InfoContext(ctx, "...1098 forms submitted",
-		slog.String("component", component),
-		slog.String("op", op),
-		slog.Any("response", res),
-	)
-
-	return res, nil
+func (t *tax1099Impl) Submit1098s(ctx context.Context, payload Submit1098sRequest) (Submit1098sResponse, error) {
+	return Submit(ctx, t, payload)
 }
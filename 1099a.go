@@ -0,0 +1,140 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ErrReportedOn1099C is returned by Check1099AAgainst1099C when a 1099-A covers a
+// debt whose 1099-C for the same year already reports the property's fair
+// market value. The IRS expects only the 1099-C in that case.
+var ErrReportedOn1099C = errors.New("debt is already reported on a 1099-C with fair market value")
+
+// Submit1099ARequest represents the JSON structure for validating or importing 1099-A forms
+type Submit1099ARequest = SubmitRequest[Form1099A]
+
+// Item1099A represents a single lender and their associated 1099-A forms
+type Item1099A = Item[Form1099A]
+
+// Form1099A represents the details of a single 1099-A form
+type Form1099A struct {
+	RecipientInfo       RecipientInfo `json:"recipientInfo"`              //Recipient Info is the borrower
+	TaxYear             TaxYear       `json:"taxYear"`                    //Tax Year is the year for which the form is being filed
+	AcctNo              string        `json:"acctNo"`                     //Account Number identifies the loan, and is required if you file more than one Form 1099-A for the same borrower
+	AcquisitionDate     Date          `json:"dateOfAcquisition,omitzero"` //Acquisition Date is box 1, the date of the lender's acquisition or knowledge of abandonment
+	PrincipalBalance    Money         `json:"principalOutstanding"`       //Principal Balance is box 2, the balance of principal outstanding
+	FairMarketValue     Money         `json:"fairMarketValue"`            //Fair Market Value is box 4, the fair market value of the property
+	PersonallyLiable    bool          `json:"personallyLiable"`           //Personally Liable is box 5, set when the borrower was personally liable for repayment
+	PropertyDescription string        `json:"propertyDescription"`        //Property Description is box 6, the address or legal description of the property
+	USPSMail            bool          `json:"uspsMail"`                   //USPS Mail is used to indicate if the form should be mailed to the borrower
+	TINCheck            bool          `json:"tinCheck"`                   //TIN Check is used to indicate if the TIN should be checked
+	EDelivery           bool          `json:"eDelivery"`                  //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn     bool          `json:"correctedReturn"`            //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099AsRequest represents the JSON structure for submitting 1099-A forms for filing
+type Submit1099AsRequest = PaymentRequest[Form1099A]
+
+// debtKey identifies a debt across form types by lender, borrower, account and
+// tax year.
+type debtKey struct {
+	payerTin, recipientTin, acctNo string
+	taxYear                        TaxYear
+}
+
+func newDebtKey(payer PayerInfo, recipient RecipientInfo, acctNo string, formYear, requestYear TaxYear) debtKey {
+	year := formYear
+	if year == "" {
+		year = requestYear
+	}
+
+	return debtKey{
+		payerTin:     payer.TaxIdentifer.Digits(),
+		recipientTin: recipient.TaxIdentifer.Digits(),
+		acctNo:       strings.TrimSpace(acctNo),
+		taxYear:      year,
+	}
+}
+
+// Check1099AAgainst1099C checks the 1099-A forms in a against the 1099-C forms
+// filed for the same year. A 1099-A is rejected with ErrReportedOn1099C when a
+// 1099-C from the same payer to the same recipient for the same account and tax
+// year carries box 7 fair market value, because that 1099-C already reports the
+// acquisition. Validate1099A, Import1099A and Submit1099As run this check
+// before sending anything.
+func Check1099AAgainst1099C(a Submit1099ARequest, cs ...Submit1099CRequest) error {
+	return check1099As(a.TaxYear, a.Items, cs)
+}
+
+func check1099As(taxYear TaxYear, items []Item1099A, cs []Submit1099CRequest) error {
+	reported := map[debtKey]bool{}
+	for _, c := range cs {
+		for _, item := range c.Items {
+			for _, form := range item.Forms {
+				if !form.FairMarketValue.IsZero() {
+					reported[newDebtKey(item.PayerInfo, form.RecipientInfo, form.AcctNo, form.TaxYear, c.TaxYear)] = true
+				}
+			}
+		}
+	}
+
+	var errs []error
+	for i, item := range items {
+		for j, form := range item.Forms {
+			key := newDebtKey(item.PayerInfo, form.RecipientInfo, form.AcctNo, form.TaxYear, taxYear)
+			if reported[key] {
+				errs = append(errs, fmt.Errorf("items[%d].forms[%d] (account %q, tax year %s): %w", i, j, key.acctNo, key.taxYear, ErrReportedOn1099C))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate1099A validates 1099-A forms like Validate, first checking them
+// against the 1099-C forms in cs with Check1099AAgainst1099C.
+func Validate1099A(ctx context.Context, client Tax1099, payload Submit1099ARequest, cs ...Submit1099CRequest) (FormResponse, error) {
+	if err := check1099As(payload.TaxYear, payload.Items, cs); err != nil {
+		return FormResponse{}, err
+	}
+
+	return Validate(ctx, client, payload)
+}
+
+// Import1099A imports 1099-A forms like Import, first checking them against
+// the 1099-C forms in cs with Check1099AAgainst1099C.
+func Import1099A(ctx context.Context, client Tax1099, payload Submit1099ARequest, cs ...Submit1099CRequest) (FormResponse, error) {
+	if err := check1099As(payload.TaxYear, payload.Items, cs); err != nil {
+		return FormResponse{}, err
+	}
+
+	return Import(ctx, client, payload)
+}
+
+// Submit1099As submits 1099-A forms like Submit, first checking them against
+// the 1099-C forms in cs with Check1099AAgainst1099C.
+func Submit1099As(ctx context.Context, client Tax1099, payload Submit1099AsRequest, cs ...Submit1099CRequest) (PaymentResponse, error) {
+	if err := check1099As(payload.TaxYear, payload.Items, cs); err != nil {
+		return PaymentResponse{}, err
+	}
+
+	return Submit(ctx, client, payload)
+}
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-A",
+		Segment: "1099a",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099A) FormName() string           { return "1099-A" }
+func (f Form1099A) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099A) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099A) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
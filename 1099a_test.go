@@ -0,0 +1,127 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func Test_Check1099AAgainst1099C(t *testing.T) {
+	payer := PayerInfo{TaxIdentifer: "12-3456789"}
+	borrower := RecipientInfo{TaxIdentifer: "987-65-4321"}
+
+	acquisition := Submit1099ARequest{
+		TaxYear: "2024",
+		Items: []Item1099A{{
+			PayerInfo: payer,
+			Forms:     []Form1099A{{RecipientInfo: borrower, AcctNo: "1001", FairMarketValue: MoneyFromCents(6100000)}},
+		}},
+	}
+
+	cancellation := func(acctNo string, taxYear TaxYear, fmv Money) Submit1099CRequest {
+		return Submit1099CRequest{
+			TaxYear: taxYear,
+			Items: []Item1099C{{
+				PayerInfo: PayerInfo{TaxIdentifer: "123456789"},
+				Forms:     []Form1099C{{RecipientInfo: RecipientInfo{TaxIdentifer: "987654321"}, AcctNo: acctNo, FairMarketValue: fmv}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		cs      []Submit1099CRequest
+		wantErr error
+	}{
+		{
+			name: "no 1099-C",
+		},
+		{
+			name:    "1099-C for the same debt with fair market value",
+			cs:      []Submit1099CRequest{cancellation("1001", "2024", MoneyFromCents(6100000))},
+			wantErr: ErrReportedOn1099C,
+		},
+		{
+			name: "1099-C for the same debt without fair market value",
+			cs:   []Submit1099CRequest{cancellation("1001", "2024", Money{})},
+		},
+		{
+			name: "1099-C for another account",
+			cs:   []Submit1099CRequest{cancellation("1002", "2024", MoneyFromCents(6100000))},
+		},
+		{
+			name: "1099-C for another year",
+			cs:   []Submit1099CRequest{cancellation("1001", "2023", MoneyFromCents(6100000))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check1099AAgainst1099C(acquisition, tt.cs...)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Check1099AAgainst1099C() error = %v, want nil", err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Check1099AAgainst1099C() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_1099A_CheckedBeforeSending(t *testing.T) {
+	payer := PayerInfo{TinType: TinTypeBusiness, TaxIdentifer: "123456789", LastNameOrBusinessName: "Lender LLC"}
+	borrower := RecipientInfo{TinType: TinTypeIndividual, TaxIdentifer: "987654321", LastNameOrBusinessName: "Borrower"}
+
+	items := []Item1099A{{PayerInfo: payer, Forms: []Form1099A{{RecipientInfo: borrower, TaxYear: "2024", AcctNo: "1001"}}}}
+	cancellation := Submit1099CRequest{
+		TaxYear: "2024",
+		Items:   []Item1099C{{PayerInfo: payer, Forms: []Form1099C{{RecipientInfo: borrower, AcctNo: "1001", FairMarketValue: MoneyFromCents(6100000)}}}},
+	}
+
+	tests := []struct {
+		name string
+		call func(client Tax1099) error
+	}{
+		{
+			name: "validate",
+			call: func(client Tax1099) error {
+				_, err := Validate1099A(context.Background(), client, Submit1099ARequest{TaxYear: "2024", Items: items}, cancellation)
+				return err
+			},
+		},
+		{
+			name: "import",
+			call: func(client Tax1099) error {
+				_, err := Import1099A(context.Background(), client, Submit1099ARequest{TaxYear: "2024", Items: items}, cancellation)
+				return err
+			},
+		},
+		{
+			name: "submit",
+			call: func(client Tax1099) error {
+				_, err := Submit1099As(context.Background(), client, Submit1099AsRequest{TaxYear: "2024", Items: items}, cancellation)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, got := newFormTestClient(t)
+
+			if err := tt.call(client); !errors.Is(err, ErrReportedOn1099C) {
+				t.Fatalf("error = %v, want %v", err, ErrReportedOn1099C)
+			}
+
+			if got.Path != "" {
+				t.Errorf("request sent to %s, want none", got.Path)
+			}
+		})
+	}
+
+	client, got := newFormTestClient(t)
+	if _, err := Validate1099A(context.Background(), client, Submit1099ARequest{TaxYear: "2024", Items: items}); err != nil {
+		t.Fatalf("Validate1099A() without a 1099-C error = %v", err)
+	}
+	if want := "/api/v1/forms/1099a/validate"; got.Path != want {
+		t.Errorf("path = %q, want %q", got.Path, want)
+	}
+}
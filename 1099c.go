@@ -0,0 +1,91 @@
+package tax1099
+
+import (
+	"log/slog"
+)
+
+// EventCode defines the allowed values for box 6 of Form 1099-C, the identifiable
+// event that caused the debt to be reported as cancelled
+type EventCode string
+
+const (
+	EventCodeBankruptcy          EventCode = "A" //Bankruptcy under Title 11
+	EventCodeJudicialDebtRelief  EventCode = "B" //Other judicial debt relief
+	EventCodeStatuteOfLimitation EventCode = "C" //Statute of limitations or expiration of deficiency period
+	EventCodeForeclosureElection EventCode = "D" //Foreclosure election
+	EventCodeProbate             EventCode = "E" //Debt relief from probate or similar proceeding
+	EventCodeAgreement           EventCode = "F" //By agreement
+	EventCodeCollectionPolicy    EventCode = "G" //Decision or policy to discontinue collection
+	EventCodeOtherDischarge      EventCode = "H" //Other actual discharge before identifiable event
+)
+
+// IsValid reports whether c is one of the event codes A through H.
+func (c EventCode) IsValid() bool {
+	return len(c) == 1 && c >= EventCodeBankruptcy && c <= EventCodeOtherDischarge
+}
+
+// Submit1099CRequest represents the JSON structure for validating or importing 1099-C forms
+type Submit1099CRequest = SubmitRequest[Form1099C]
+
+// Item1099C represents a single creditor and their associated 1099-C forms
+type Item1099C = Item[Form1099C]
+
+// Form1099C represents the details of a single 1099-C form
+type Form1099C struct {
+	RecipientInfo    RecipientInfo `json:"recipientInfo"`                    //Recipient Info is the debtor whose debt was cancelled
+	TaxYear          TaxYear       `json:"taxYear"`                          //Tax Year is the year for which the form is being filed
+	AcctNo           string        `json:"acctNo"`                           //Account Number is required if you file more than one Form 1099-C for the same debtor
+	EventDate        Date          `json:"dateOfIdentifiableEvent,omitzero"` //Event Date is box 1, the date of the identifiable event
+	DebtDischarged   Money         `json:"amountOfDebtDischarged"`           //Debt Discharged is box 2, the amount of debt discharged
+	InterestIncluded Money         `json:"interestIfIncluded"`               //Interest Included is box 3, the interest included in box 2
+	DebtDescription  string        `json:"debtDescription"`                  //Debt Description is box 4
+	PersonallyLiable bool          `json:"personallyLiable"`                 //Personally Liable is box 5, set when the debtor was personally liable for repayment
+	EventCode        EventCode     `json:"identifiableEventCode"`            //Event Code is box 6, the identifiable event code A through H
+	FairMarketValue  Money         `json:"fairMarketValue"`                  //Fair Market Value is box 7, the fair market value of property when the debt arose from a foreclosure or abandonment
+	USPSMail         bool          `json:"uspsMail"`                         //USPS Mail is used to indicate if the form should be mailed to the debtor
+	TINCheck         bool          `json:"tinCheck"`                         //TIN Check is used to indicate if the TIN should be checked
+	EDelivery        bool          `json:"eDelivery"`                        //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn  bool          `json:"correctedReturn"`                  //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099CsRequest represents the JSON structure for submitting 1099-C forms for filing
+type Submit1099CsRequest = PaymentRequest[Form1099C]
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-C",
+		Segment: "1099c",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099C) FormName() string           { return "1099-C" }
+func (f Form1099C) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099C) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099C) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
+
+// Validate checks the form without calling the API: the recipient, that amounts
+// are not negative, that EventDate is a real date, that EventCode is one of A
+// through H, and the TaxYear format.
+func (f Form1099C) Validate() []ValidationError {
+	errs := within("recipientInfo", f.RecipientInfo.Validate())
+
+	var v validator
+
+	if f.TaxYear != "" && !f.TaxYear.IsValid() {
+		v.add("taxYear", "must be a four digit year")
+	}
+
+	v.nonNegative("amountOfDebtDischarged", f.DebtDischarged)
+	v.nonNegative("interestIfIncluded", f.InterestIncluded)
+	v.nonNegative("fairMarketValue", f.FairMarketValue)
+	v.date("dateOfIdentifiableEvent", f.EventDate)
+
+	if v.required("identifiableEventCode", string(f.EventCode)) && !f.EventCode.IsValid() {
+		v.add("identifiableEventCode", "must be one of A through H, got %q", f.EventCode)
+	}
+
+	return append(errs, v.errs...)
+}
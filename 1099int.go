@@ -0,0 +1,58 @@
+package tax1099
+
+import (
+	"log/slog"
+)
+
+// Submit1099INTRequest represents the JSON structure for validating or importing 1099-INT forms
+type Submit1099INTRequest = SubmitRequest[Form1099INT]
+
+// Item1099INT represents a single payer and their associated 1099-INT forms
+type Item1099INT = Item[Form1099INT]
+
+// Form1099INT represents the details of a single 1099-INT form
+type Form1099INT struct {
+	RecipientInfo               RecipientInfo      `json:"recipientInfo"`                //Recipient Info is the borrower or investor who received the interest
+	TaxYear                     TaxYear            `json:"taxYear"`                      //Tax Year is the year for which the form is being filed
+	AcctNo                      string             `json:"acctNo"`                       //Account Number is required if you file more than one Form 1099-INT for the same recipient
+	PayerRTN                    string             `json:"payerRtn,omitempty"`           //Payer RTN is the payer's routing and transit number, optional
+	SecondTinNotice             bool               `json:"secondTinNotice"`              //Second TIN Notice is used to indicate the IRS has notified you twice that the recipient's TIN is incorrect
+	FATCAFilingRequirement      bool               `json:"fatcaFilingRequirement"`       //FATCA Filing Requirement is used to indicate the form is filed to satisfy FATCA reporting
+	InterestIncome              Money              `json:"interestIncome"`               //Interest Income is box 1, taxable interest not included in box 3
+	EarlyWithdrawalPenalty      Money              `json:"earlyWithdrawalPenalty"`       //Early Withdrawal Penalty is box 2, interest or principal forfeited for early withdrawal
+	USSavingsBondInterest       Money              `json:"usSavingsBondInterest"`        //US Savings Bond Interest is box 3, interest on U.S. Savings Bonds and Treasury obligations
+	FederalIncomeTaxWithheld    Money              `json:"federalIncomeTaxWithheld"`     //Federal Income Tax Withheld is box 4, including backup withholding
+	InvestmentExpenses          Money              `json:"investmentExpenses"`           //Investment Expenses is box 5
+	ForeignTaxPaid              Money              `json:"foreignTaxPaid"`               //Foreign Tax Paid is box 6
+	ForeignCountry              string             `json:"foreignCountry,omitempty"`     //Foreign Country is box 7, the country or U.S. possession the foreign tax was paid to
+	TaxExemptInterest           Money              `json:"taxExemptInterest"`            //Tax-Exempt Interest is box 8
+	PrivateActivityBondInterest Money              `json:"privateActivityBondInterest"`  //Private Activity Bond Interest is box 9, specified private activity bond interest
+	MarketDiscount              Money              `json:"marketDiscount"`               //Market Discount is box 10
+	BondPremium                 Money              `json:"bondPremium"`                  //Bond Premium is box 11
+	BondPremiumTreasury         Money              `json:"bondPremiumTreasury"`          //Bond Premium Treasury is box 12, bond premium on Treasury obligations
+	BondPremiumTaxExempt        Money              `json:"bondPremiumTaxExempt"`         //Bond Premium Tax Exempt is box 13, bond premium on tax-exempt bonds
+	TaxExemptBondCUSIP          string             `json:"taxExemptBondCusip,omitempty"` //Tax-Exempt Bond CUSIP is box 14, the CUSIP number of the tax-exempt or tax credit bond
+	States                      []StateWithholding `json:"states,omitempty"`             //States are boxes 15 through 17, up to two state rows
+	USPSMail                    bool               `json:"uspsMail"`                     //USPS Mail is used to indicate if the form should be mailed to the recipient
+	TINCheck                    bool               `json:"tinCheck"`                     //TIN Check is used to indicate if the TIN should be checked
+	EDelivery                   bool               `json:"eDelivery"`                    //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn             bool               `json:"correctedReturn"`              //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099INTsRequest represents the JSON structure for submitting 1099-INT forms for filing
+type Submit1099INTsRequest = PaymentRequest[Form1099INT]
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-INT",
+		Segment: "1099int",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099INT) FormName() string           { return "1099-INT" }
+func (f Form1099INT) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099INT) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099INT) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
@@ -0,0 +1,57 @@
+package tax1099
+
+import (
+	"log/slog"
+)
+
+// Submit1099MISCRequest represents the JSON structure for validating or importing 1099-MISC forms
+type Submit1099MISCRequest = SubmitRequest[Form1099MISC]
+
+// Item1099MISC represents a single payer and their associated 1099-MISC forms
+type Item1099MISC = Item[Form1099MISC]
+
+// Form1099MISC represents the details of a single 1099-MISC form
+type Form1099MISC struct {
+	RecipientInfo            RecipientInfo      `json:"recipientInfo"`            //Recipient Info is the person or business that was paid
+	TaxYear                  TaxYear            `json:"taxYear"`                  //Tax Year is the year for which the form is being filed
+	AcctNo                   string             `json:"acctNo"`                   //Account Number is required if you file more than one Form 1099-MISC for the same recipient
+	SecondTinNotice          bool               `json:"secondTinNotice"`          //Second TIN Notice is used to indicate the IRS has notified you twice that the recipient's TIN is incorrect
+	Rents                    Money              `json:"rents"`                    //Rents is box 1
+	Royalties                Money              `json:"royalties"`                //Royalties is box 2
+	OtherIncome              Money              `json:"otherIncome"`              //Other Income is box 3
+	FederalIncomeTaxWithheld Money              `json:"federalIncomeTaxWithheld"` //Federal Income Tax Withheld is box 4, including backup withholding
+	FishingBoatProceeds      Money              `json:"fishingBoatProceeds"`      //Fishing Boat Proceeds is box 5
+	MedicalPayments          Money              `json:"medicalPayments"`          //Medical Payments is box 6, medical and health care payments
+	DirectSales              bool               `json:"directSales"`              //Direct Sales is box 7, set when you made direct sales of $5,000 or more of consumer products for resale
+	SubstitutePayments       Money              `json:"substitutePayments"`       //Substitute Payments is box 8, substitute payments in lieu of dividends or interest
+	CropInsuranceProceeds    Money              `json:"cropInsuranceProceeds"`    //Crop Insurance Proceeds is box 9
+	AttorneyProceeds         Money              `json:"attorneyProceeds"`         //Attorney Proceeds is box 10, gross proceeds paid to an attorney
+	FishPurchased            Money              `json:"fishPurchased"`            //Fish Purchased is box 11, fish purchased for resale
+	Section409ADeferrals     Money              `json:"section409ADeferrals"`     //Section 409A Deferrals is box 12
+	FATCAFilingRequirement   bool               `json:"fatcaFilingRequirement"`   //FATCA Filing Requirement is box 13
+	ExcessGoldenParachute    Money              `json:"excessGoldenParachute"`    //Excess Golden Parachute is box 14, excess golden parachute payments
+	NonqualifiedDeferredComp Money              `json:"nonqualifiedDeferredComp"` //Nonqualified Deferred Comp is box 15, nonqualified deferred compensation
+	States                   []StateWithholding `json:"states,omitempty"`         //States are boxes 16 through 18, up to two state rows
+	USPSMail                 bool               `json:"uspsMail"`                 //USPS Mail is used to indicate if the form should be mailed to the recipient
+	TINCheck                 bool               `json:"tinCheck"`                 //TIN Check is used to indicate if the TIN should be checked
+	EDelivery                bool               `json:"eDelivery"`                //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn          bool               `json:"correctedReturn"`          //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099MISCsRequest represents the JSON structure for submitting 1099-MISC forms for filing
+type Submit1099MISCsRequest = PaymentRequest[Form1099MISC]
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-MISC",
+		Segment: "1099misc",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099MISC) FormName() string           { return "1099-MISC" }
+func (f Form1099MISC) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099MISC) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099MISC) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
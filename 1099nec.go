@@ -0,0 +1,46 @@
+package tax1099
+
+import (
+	"log/slog"
+)
+
+// Submit1099NECRequest represents the JSON structure for validating or importing 1099-NEC forms
+type Submit1099NECRequest = SubmitRequest[Form1099NEC]
+
+// Item1099NEC represents a single payer and their associated 1099-NEC forms
+type Item1099NEC = Item[Form1099NEC]
+
+// Form1099NEC represents the details of a single 1099-NEC form
+type Form1099NEC struct {
+	RecipientInfo            RecipientInfo      `json:"recipientInfo"`            //Recipient Info is the contractor who was paid
+	TaxYear                  TaxYear            `json:"taxYear"`                  //Tax Year is the year for which the form is being filed
+	AcctNo                   string             `json:"acctNo"`                   //Account Number is required if you file more than one Form 1099-NEC for the same recipient
+	SecondTinNotice          bool               `json:"secondTinNotice"`          //Second TIN Notice is used to indicate the IRS has notified you twice that the recipient's TIN is incorrect
+	NonemployeeCompensation  Money              `json:"nonEmployeeCompensation"`  //Nonemployee Compensation is box 1, the amount paid to the contractor for services
+	DirectSales              bool               `json:"directSales"`              //Direct Sales is box 2, set when you made direct sales of $5,000 or more of consumer products for resale
+	ExcessGoldenParachute    Money              `json:"excessGoldenParachute"`    //Excess Golden Parachute is box 3, excess golden parachute payments
+	FederalIncomeTaxWithheld Money              `json:"federalIncomeTaxWithheld"` //Federal Income Tax Withheld is box 4, including backup withholding
+	States                   []StateWithholding `json:"states,omitempty"`         //States are boxes 5 through 7, up to two state rows
+	USPSMail                 bool               `json:"uspsMail"`                 //USPS Mail is used to indicate if the form should be mailed to the recipient
+	TINCheck                 bool               `json:"tinCheck"`                 //TIN Check is used to indicate if the TIN should be checked
+	EDelivery                bool               `json:"eDelivery"`                //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn          bool               `json:"correctedReturn"`          //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099NECsRequest represents the JSON structure for submitting 1099-NEC forms for filing
+type Submit1099NECsRequest = PaymentRequest[Form1099NEC]
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-NEC",
+		Segment: "1099nec",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099NEC) FormName() string           { return "1099-NEC" }
+func (f Form1099NEC) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099NEC) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099NEC) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
@@ -0,0 +1,46 @@
+package tax1099
+
+import (
+	"log/slog"
+)
+
+// Submit1099SRequest represents the JSON structure for validating or importing 1099-S forms
+type Submit1099SRequest = SubmitRequest[Form1099S]
+
+// Item1099S represents a single filer and their associated 1099-S forms
+type Item1099S = Item[Form1099S]
+
+// Form1099S represents the details of a single 1099-S form
+type Form1099S struct {
+	RecipientInfo       RecipientInfo `json:"recipientInfo"`          //Recipient Info is the transferor, the seller of the property
+	TaxYear             TaxYear       `json:"taxYear"`                //Tax Year is the year for which the form is being filed
+	AcctNo              string        `json:"acctNo"`                 //Account Number is required if you file more than one Form 1099-S for the same transferor
+	ClosingDate         Date          `json:"dateOfClosing,omitzero"` //Closing Date is box 1, the date of closing
+	GrossProceeds       Money         `json:"grossProceeds"`          //Gross Proceeds is box 2
+	PropertyAddress     string        `json:"propertyAddress"`        //Property Address is box 3, the address or legal description of the property
+	PropertyOrServices  bool          `json:"propertyOrServices"`     //Property Or Services is box 4, set when the transferor received or will receive property or services as part of the consideration
+	ForeignTransferor   bool          `json:"foreignTransferor"`      //Foreign Transferor is box 5, set when the transferor is a foreign person
+	BuyersRealEstateTax Money         `json:"buyersRealEstateTax"`    //Buyers Real Estate Tax is box 6, the buyer's part of real estate tax
+	USPSMail            bool          `json:"uspsMail"`               //USPS Mail is used to indicate if the form should be mailed to the transferor
+	TINCheck            bool          `json:"tinCheck"`               //TIN Check is used to indicate if the TIN should be checked
+	EDelivery           bool          `json:"eDelivery"`              //E-Delivery is used to indicate if the form should be delivered electronically
+	CorrectedReturn     bool          `json:"correctedReturn"`        //Corrected Return is used to indicate if the form is a corrected return
+}
+
+// Submit1099SsRequest represents the JSON structure for submitting 1099-S forms for filing
+type Submit1099SsRequest = PaymentRequest[Form1099S]
+
+func init() {
+	RegisterForm(FormSpec{
+		Name:    "1099-S",
+		Segment: "1099s",
+		BaseURLs: map[Environment]string{
+			EnvironmentStaging: "https://apiforms.1099cloud.com/api/v1",
+		},
+	})
+}
+
+func (Form1099S) FormName() string           { return "1099-S" }
+func (f Form1099S) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f Form1099S) Recipient() RecipientInfo { return f.RecipientInfo }
+func (f Form1099S) LogValue() slog.Value     { return formLogValue(f, f.AcctNo) }
@@ -0,0 +1,199 @@
+package tax1099
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// zipMagic is the prefix of a ZIP archive's first local file header.
+var zipMagic = []byte("PK\x03\x04")
+
+// ErrArchiveResponse is returned by the single-PDF download methods when
+// Tax1099 answers with a ZIP bundle, as it can for IsAllCopies requests. Those
+// methods return one document and cannot say which entry of a bundle the caller
+// wanted, so bundles are only unpacked by DownloadFilledForms.
+var ErrArchiveResponse = errors.New("response is a ZIP archive, use DownloadFilledForms")
+
+// FormFile is one PDF from a download: either the whole response, or one entry
+// of a ZIP bundle.
+type FormFile struct {
+	Name     string //Name is the entry name inside the ZIP, or empty for a plain PDF response
+	FormID   uint   //FormID is parsed from the entry name when it carries one, otherwise zero
+	CopyType string //CopyType is the copy parsed from the entry name, such as "Copy B" or "Payer", otherwise empty
+	Data     []byte //Data is the PDF document
+}
+
+var (
+	formIDPattern     = regexp.MustCompile(`(?i)(?:^|_)FormId_(\d+)(?:_|$)`)
+	copyLetterPattern = regexp.MustCompile(`(?i)(?:^|[^a-z])copy[ _-]?([a-z0-9]{1,2})(?:[^a-z0-9]|$)`)
+	namedCopyPattern  = regexp.MustCompile(`(?i)(payer|recipient|state|filer)[ _-]?copy`)
+)
+
+// parseFormFileName pulls the form ID and copy type out of a ZIP entry name
+// such as "1098_FormId_123456_CopyB.pdf". The form ID is only taken from an
+// explicit "FormId_<digits>" part, so a year or account number elsewhere in the
+// name is never mistaken for one. Names without those parts leave the fields
+// empty.
+func parseFormFileName(name string) (formID uint, copyType string) {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+
+	if m := formIDPattern.FindStringSubmatch(base); m != nil {
+		if id, err := strconv.ParseUint(m[1], 10, 0); err == nil {
+			formID = uint(id)
+		}
+	}
+
+	if m := copyLetterPattern.FindStringSubmatch(base); m != nil {
+		copyType = "Copy " + strings.ToUpper(m[1])
+	} else if m := namedCopyPattern.FindStringSubmatch(base); m != nil {
+		copyType = strings.ToUpper(m[1][:1]) + strings.ToLower(m[1][1:])
+	}
+
+	return formID, copyType
+}
+
+// formFilesDecoder accepts either a PDF or a ZIP of PDFs.
+type formFilesDecoder struct {
+	files   *[]FormFile
+	maxSize int64
+}
+
+func (formFilesDecoder) accept() string { return "application/pdf, application/zip" }
+
+func (d formFilesDecoder) decode(op, url string, resp *http.Response) error {
+	if err := checkContentLength(url, resp, d.maxSize); err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body := io.Reader(resp.Body)
+	if d.maxSize > 0 {
+		body = io.LimitReader(resp.Body, d.maxSize+1)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body from %s: %w", url, err)
+	}
+
+	if d.maxSize > 0 && int64(len(data)) > d.maxSize {
+		return fmt.Errorf("response from %s: %w", url, ErrDownloadTooLarge)
+	}
+
+	switch {
+	case bytes.HasPrefix(data, pdfMagic):
+		*d.files = []FormFile{{Data: data}}
+		return nil
+	case bytes.HasPrefix(data, zipMagic):
+		files, err := readFormArchive(data, d.maxSize)
+		if err != nil {
+			return fmt.Errorf("response from %s: %w", url, err)
+		}
+
+		*d.files = files
+		return nil
+	}
+
+	return fmt.Errorf("response from %s is not a PDF or ZIP, body: %s", url, truncateForError(data))
+}
+
+// readFormArchive extracts the PDFs from a ZIP bundle, checking each entry is a
+// PDF. maxSize, when set, bounds the total uncompressed size.
+func readFormArchive(data []byte, maxSize int64) ([]FormFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ZIP archive: %w", err)
+	}
+
+	var files []FormFile
+	var total int64
+
+	for _, entry := range zr.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in ZIP archive: %w", entry.Name, err)
+		}
+
+		r := io.Reader(rc)
+		if maxSize > 0 {
+			r = io.LimitReader(rc, maxSize-total+1)
+		}
+
+		content, err := io.ReadAll(r)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in ZIP archive: %w", entry.Name, err)
+		}
+
+		total += int64(len(content))
+		if maxSize > 0 && total > maxSize {
+			return nil, ErrDownloadTooLarge
+		}
+
+		if !bytes.HasPrefix(content, pdfMagic) {
+			return nil, fmt.Errorf("%s in ZIP archive is not a PDF", entry.Name)
+		}
+
+		formID, copyType := parseFormFileName(entry.Name)
+		files = append(files, FormFile{
+			Name:     entry.Name,
+			FormID:   formID,
+			CopyType: copyType,
+			Data:     content,
+		})
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New("ZIP archive contains no PDFs")
+	}
+
+	return files, nil
+}
+
+// DownloadFilledForms downloads filled forms like DownloadFilledForm, but also
+// accepts the ZIP bundles Tax1099 can return for bulk requests such as
+// IsAllCopies. A plain PDF response is returned as a single FormFile.
+func (t *tax1099Impl) DownloadFilledForms(ctx context.Context, payload DownloadFormRequest) ([]FormFile, error) {
+	const op = "tax1099.download_filled_form"
+
+	if err := payload.validate(); err != nil {
+		return nil, err
+	}
+
+	t.log().InfoContext(ctx, "Downloading filled forms...",
+		slog.String("component", component),
+		slog.String("op", op),
+	)
+
+	url, err := t.endpointURL(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FormFile
+	if err := t.do(ctx, op, url, payload, formFilesDecoder{files: &files, maxSize: t.maxDownloadSize}); err != nil {
+		return nil, err
+	}
+
+	t.log().InfoContext(ctx, "...filled forms downloaded",
+		slog.String("component", component),
+		slog.String("op", op),
+		slog.Int("files", len(files)),
+	)
+
+	return files, nil
+}
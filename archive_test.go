@@ -0,0 +1,175 @@
+package tax1099
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testZip(t *testing.T, entries map[string]string, order ...string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("zip Create() error = %v", err)
+		}
+		w.Write([]byte(entries[name]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip Close() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func Test_parseFormFileName(t *testing.T) {
+	tests := []struct {
+		name         string
+		wantFormID   uint
+		wantCopyType string
+	}{
+		{"1098_FormId_123456_CopyB.pdf", 123456, "Copy B"},
+		{"forms/2024/1099NEC_FormId_98765_Copy-C.pdf", 98765, "Copy C"},
+		{"1098_formid_42.pdf", 42, ""},
+		{"forms/2024/id-98765-copy-c.pdf", 0, "Copy C"},
+		{"1098_2024_554433_Copy1.pdf", 0, "Copy 1"},
+		{"1098_Acct_10012345_CopyB.pdf", 0, "Copy B"},
+		{"1098_FormId_12x_CopyB.pdf", 0, "Copy B"},
+		{"PayerCopy_2024.pdf", 0, "Payer"},
+		{"recipient-copy.pdf", 0, "Recipient"},
+		{"2024 forms.pdf", 0, ""},
+	}
+	for _, tt := range tests {
+		formID, copyType := parseFormFileName(tt.name)
+		if formID != tt.wantFormID || copyType != tt.wantCopyType {
+			t.Errorf("parseFormFileName(%q) = %d, %q, want %d, %q", tt.name, formID, copyType, tt.wantFormID, tt.wantCopyType)
+		}
+	}
+}
+
+func Test_tax1099Impl_DownloadFilledForms(t *testing.T) {
+	tests := []struct {
+		name             string
+		mockResponseBody func(t *testing.T) []byte
+		maxSize          int64
+		wantNames        []string
+		wantErrMsg       string
+		wantErr          error
+	}{
+		{
+			name: "single PDF is returned as one file",
+			mockResponseBody: func(t *testing.T) []byte {
+				return []byte("%PDF-1.4 mock pdf content")
+			},
+			wantNames: []string{""},
+		},
+		{
+			name: "ZIP bundle is returned entry by entry",
+			mockResponseBody: func(t *testing.T) []byte {
+				return testZip(t, map[string]string{
+					"1098_FormId_1001_CopyB.pdf": "%PDF-1.4 copy b",
+					"1098_FormId_1001_CopyC.pdf": "%PDF-1.4 copy c",
+				}, "1098_FormId_1001_CopyB.pdf", "1098_FormId_1001_CopyC.pdf")
+			},
+			wantNames: []string{"1098_FormId_1001_CopyB.pdf", "1098_FormId_1001_CopyC.pdf"},
+		},
+		{
+			name: "ZIP entry that is not a PDF is rejected",
+			mockResponseBody: func(t *testing.T) []byte {
+				return testZip(t, map[string]string{
+					"1098_FormId_1001_CopyB.pdf": "%PDF-1.4 copy b",
+					"errors.json":                `{"message":"form 1002 failed"}`,
+				}, "1098_FormId_1001_CopyB.pdf", "errors.json")
+			},
+			wantErrMsg: "errors.json in ZIP archive is not a PDF",
+		},
+		{
+			name: "ZIP bundle over the size limit is rejected",
+			mockResponseBody: func(t *testing.T) []byte {
+				return testZip(t, map[string]string{
+					"big.pdf": "%PDF-1.4 " + strings.Repeat("x", 4096),
+				}, "big.pdf")
+			},
+			maxSize: 2048,
+			wantErr: ErrDownloadTooLarge,
+		},
+		{
+			name: "JSON body is rejected",
+			mockResponseBody: func(t *testing.T) []byte {
+				return []byte(`{"message":"form not found"}`)
+			},
+			wantErrMsg: "is not a PDF or ZIP",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.mockResponseBody(t)
+			server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+				w.Write(body)
+			})
+
+			client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+				WithBaseURL(UrlMain, server.URL+"/api/v1"),
+				WithMaxDownloadSize(tt.maxSize),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			files, gotErr := client.DownloadFilledForms(context.Background(), DownloadFormRequest{PayerTin: "12-3456789", TaxYear: "2024", FormType: "1098", IsAllCopies: true})
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(gotErr, tt.wantErr) {
+					t.Fatalf("DownloadFilledForms() error = %v, want %v", gotErr, tt.wantErr)
+				}
+				return
+			case tt.wantErrMsg != "":
+				if gotErr == nil || !strings.Contains(gotErr.Error(), tt.wantErrMsg) {
+					t.Fatalf("DownloadFilledForms() error = %v, want it to mention %q", gotErr, tt.wantErrMsg)
+				}
+				return
+			}
+
+			if gotErr != nil {
+				t.Fatalf("DownloadFilledForms() error = %v, want nil", gotErr)
+			}
+
+			if len(files) != len(tt.wantNames) {
+				t.Fatalf("DownloadFilledForms() returned %d files, want %d", len(files), len(tt.wantNames))
+			}
+			for i, name := range tt.wantNames {
+				if files[i].Name != name || !bytes.HasPrefix(files[i].Data, []byte("%PDF")) {
+					t.Errorf("file %d = %q, want %q holding a PDF", i, files[i].Name, name)
+				}
+			}
+		})
+	}
+}
+
+func Test_tax1099Impl_DownloadFilledForm_ZipResponse(t *testing.T) {
+	body := testZip(t, map[string]string{"copy.pdf": "%PDF-1.4 copy"}, "copy.pdf")
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	})
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = client.DownloadFilledForm(context.Background(), DownloadFormRequest{FormID: 1, FormType: "1098", IsAllCopies: true})
+	if !errors.Is(err, ErrArchiveResponse) {
+		t.Errorf("DownloadFilledForm() error = %v, want %v", err, ErrArchiveResponse)
+	}
+}
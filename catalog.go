@@ -0,0 +1,230 @@
+package tax1099
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBuiltinEnvironment is returned by RegisterEnvironment for EnvironmentStaging
+// and EnvironmentProduction, which every client in the process shares.
+var ErrBuiltinEnvironment = errors.New("built-in environments cannot be changed")
+
+// ErrUnknownEndpoint is returned when the client's environment has no endpoint
+// for an operation, or no base URL for the host that serves it.
+var ErrUnknownEndpoint = errors.New("no endpoint for operation")
+
+// Endpoint is where an operation is served: a host and a path under that host's
+// base URL.
+type Endpoint struct {
+	UrlType UrlType //UrlType is the host serving the operation
+	Path    string  //Path is appended to the host's base URL, e.g. "forms/1098/validate"
+}
+
+// EnvironmentConfig declares the hosts and endpoints of an environment.
+type EnvironmentConfig struct {
+	Inherits  Environment         //Inherits names the environment used for any host or endpoint not declared here
+	Hosts     map[UrlType]string  //Hosts maps each host to its base URL, including the API version path
+	Endpoints map[string]Endpoint //Endpoints maps each operation, e.g. "tax1099.validate_1098", to its endpoint
+}
+
+// formPathPrefix is the first segment of the validate and import paths of every
+// form. Tax1099's staging hosts use "forms" where production uses "form".
+var formPathPrefix = map[Environment]string{
+	EnvironmentStaging:    "forms",
+	EnvironmentProduction: "form",
+}
+
+var catalog = struct {
+	sync.RWMutex
+	envs map[Environment]*EnvironmentConfig
+}{envs: map[Environment]*EnvironmentConfig{
+	EnvironmentStaging: {
+		Hosts: map[UrlType]string{
+			UrlMain:    "https://tax1099api.1099cloud.com/api/v1",
+			UrlPayment: "https://apipayment.1099cloud.com/api/v1",
+		},
+		Endpoints: map[string]Endpoint{
+			"tax1099.authorize":            {UrlMain, "login"},
+			"tax1099.download_filled_form": {UrlMain, "pdf/forms/getpdfs"},
+		},
+	},
+	EnvironmentProduction: {
+		Hosts: map[UrlType]string{
+			UrlMain:    "https://app.tax1099.com/api/v1",
+			UrlPayment: "https://apipayment.tax1099.com/api/v1",
+		},
+		Endpoints: map[string]Endpoint{
+			"tax1099.authorize":            {UrlMain, "login"},
+			"tax1099.download_filled_form": {UrlMain, "pdf/forms/getpdfs"},
+		},
+	},
+}}
+
+// RegisterEnvironment adds the hosts and endpoints in cfg to env, creating env
+// if needed. Entries already declared for env are replaced; a non-empty Inherits
+// replaces the inherited environment. Use it to describe a custom environment
+// such as a local mock:
+//
+//	err := tax1099.RegisterEnvironment("local", tax1099.EnvironmentConfig{
+//		Inherits: tax1099.EnvironmentStaging,
+//		Hosts:    map[tax1099.UrlType]string{tax1099.UrlMain: "http://localhost:8080/api/v1"},
+//	})
+//
+// The built-in EnvironmentStaging and EnvironmentProduction cannot be changed,
+// so one package cannot redirect another's production traffic; they return
+// ErrBuiltinEnvironment. Register a custom environment that inherits one of
+// them instead, or use WithBaseURL for a single client.
+func RegisterEnvironment(env Environment, cfg EnvironmentConfig) error {
+	if env == EnvironmentStaging || env == EnvironmentProduction {
+		return fmt.Errorf("%s: %w", env, ErrBuiltinEnvironment)
+	}
+
+	registerEnvironment(env, cfg)
+	return nil
+}
+
+// registerEnvironment merges cfg into env without RegisterEnvironment's check,
+// for the package's own registrations.
+func registerEnvironment(env Environment, cfg EnvironmentConfig) {
+	catalog.Lock()
+	defer catalog.Unlock()
+
+	current, ok := catalog.envs[env]
+	if !ok {
+		current = &EnvironmentConfig{Hosts: map[UrlType]string{}, Endpoints: map[string]Endpoint{}}
+		catalog.envs[env] = current
+	}
+
+	if cfg.Inherits != "" {
+		current.Inherits = cfg.Inherits
+	}
+
+	for urlType, baseUrl := range cfg.Hosts {
+		current.Hosts[urlType] = baseUrl
+	}
+
+	for op, endpoint := range cfg.Endpoints {
+		current.Endpoints[op] = endpoint
+	}
+}
+
+// EnvironmentCatalog returns every host and endpoint of env, including those it
+// inherits. It reports false if env has not been registered.
+func EnvironmentCatalog(env Environment) (EnvironmentConfig, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+
+	if _, ok := catalog.envs[env]; !ok {
+		return EnvironmentConfig{}, false
+	}
+
+	cfg := EnvironmentConfig{Hosts: map[UrlType]string{}, Endpoints: map[string]Endpoint{}}
+	for _, c := range catalogChain(env) {
+		for urlType, baseUrl := range c.Hosts {
+			if _, ok := cfg.Hosts[urlType]; !ok {
+				cfg.Hosts[urlType] = baseUrl
+			}
+		}
+
+		for op, endpoint := range c.Endpoints {
+			if _, ok := cfg.Endpoints[op]; !ok {
+				cfg.Endpoints[op] = endpoint
+			}
+		}
+	}
+
+	return cfg, true
+}
+
+// catalogChain returns env's config followed by those it inherits from. The
+// caller must hold the catalog lock.
+func catalogChain(env Environment) []*EnvironmentConfig {
+	var chain []*EnvironmentConfig
+	seen := map[Environment]bool{}
+
+	for env != "" && !seen[env] {
+		seen[env] = true
+
+		cfg, ok := catalog.envs[env]
+		if !ok {
+			break
+		}
+
+		chain = append(chain, cfg)
+		env = cfg.Inherits
+	}
+
+	return chain
+}
+
+func lookupEndpoint(env Environment, op string) (Endpoint, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+
+	for _, cfg := range catalogChain(env) {
+		if endpoint, ok := cfg.Endpoints[op]; ok {
+			return endpoint, true
+		}
+	}
+
+	return Endpoint{}, false
+}
+
+func lookupHost(env Environment, urlType UrlType) (string, bool) {
+	catalog.RLock()
+	defer catalog.RUnlock()
+
+	for _, cfg := range catalogChain(env) {
+		if baseUrl, ok := cfg.Hosts[urlType]; ok {
+			return baseUrl, true
+		}
+	}
+
+	return "", false
+}
+
+// registerFormEndpoints adds the hosts and endpoints of a form type to the
+// catalog.
+func registerFormEndpoints(spec FormSpec) {
+	for env, baseUrl := range spec.BaseURLs {
+		registerEnvironment(env, EnvironmentConfig{Hosts: map[UrlType]string{spec.UrlType: baseUrl}})
+	}
+
+	for env, prefix := range formPathPrefix {
+		registerEnvironment(env, EnvironmentConfig{Endpoints: map[string]Endpoint{
+			formOp("validate", spec): {spec.UrlType, prefix + "/" + spec.Segment + "/validate"},
+			formOp("import", spec):   {spec.UrlType, prefix + "/importonly/" + spec.Segment},
+			formOp("submit", spec):   {UrlPayment, "payment/forms/import/submit/" + spec.Segment},
+		}})
+	}
+}
+
+// environment returns the client's environment if it is in the catalog and
+// staging otherwise.
+func (t *tax1099Impl) environment() Environment {
+	catalog.RLock()
+	defer catalog.RUnlock()
+
+	if _, ok := catalog.envs[t.env]; ok {
+		return t.env
+	}
+
+	return EnvironmentStaging
+}
+
+// endpointURL returns the full URL of op in the client's environment.
+func (t *tax1099Impl) endpointURL(op string) (string, error) {
+	endpoint, ok := lookupEndpoint(t.environment(), op)
+	if !ok {
+		return "", fmt.Errorf("%s in environment %q: %w", op, t.environment(), ErrUnknownEndpoint)
+	}
+
+	if _, ok := t.baseURLs[endpoint.UrlType]; !ok {
+		if _, ok := lookupHost(t.environment(), endpoint.UrlType); !ok {
+			return "", fmt.Errorf("%s in environment %q has no base URL for host %q: %w", op, t.environment(), endpoint.UrlType, ErrUnknownEndpoint)
+		}
+	}
+
+	return t.generateFullUrl(endpoint.UrlType, endpoint.Path), nil
+}
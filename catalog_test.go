@@ -0,0 +1,137 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_EnvironmentCatalog(t *testing.T) {
+	tests := []struct {
+		env  Environment
+		op   string
+		want Endpoint
+	}{
+		{EnvironmentStaging, "tax1099.authorize", Endpoint{UrlMain, "login"}},
+		{EnvironmentStaging, "tax1099.validate_1098", Endpoint{Url1098, "forms/1098/validate"}},
+		{EnvironmentProduction, "tax1099.validate_1098", Endpoint{Url1098, "form/1098/validate"}},
+		{EnvironmentProduction, "tax1099.import_1099nec", Endpoint{"1099nec", "form/importonly/1099nec"}},
+		{EnvironmentProduction, "tax1099.submit_1098s", Endpoint{UrlPayment, "payment/forms/import/submit/1098"}},
+	}
+	for _, tt := range tests {
+		cfg, ok := EnvironmentCatalog(tt.env)
+		if !ok {
+			t.Fatalf("EnvironmentCatalog(%s) not found", tt.env)
+		}
+
+		if got := cfg.Endpoints[tt.op]; got != tt.want {
+			t.Errorf("EnvironmentCatalog(%s).Endpoints[%s] = %+v, want %+v", tt.env, tt.op, got, tt.want)
+		}
+	}
+
+	if _, ok := EnvironmentCatalog("unregistered"); ok {
+		t.Errorf("EnvironmentCatalog(unregistered) found, want false")
+	}
+}
+
+func Test_RegisterEnvironment_CustomEnvironment(t *testing.T) {
+	var gotPath string
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write([]byte(`{"totalCount":1}`))
+	})
+
+	const local Environment = "catalog-test-local"
+	err := RegisterEnvironment(local, EnvironmentConfig{
+		Inherits: EnvironmentStaging,
+		Hosts: map[UrlType]string{
+			UrlMain: server.URL + "/api/v1",
+			Url1098: server.URL + "/api/v1",
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterEnvironment() error = %v", err)
+	}
+
+	client, err := New(context.Background(), local, "user", "pass", "key", time.Second, WithClock(filingSeason))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.Validate1098(context.Background(), Submit1098Request{TaxYear: "2024"}); err != nil {
+		t.Fatalf("Validate1098() error = %v", err)
+	}
+
+	if want := "/api/v1/forms/1098/validate"; gotPath != want {
+		t.Errorf("path = %q, want %q", gotPath, want)
+	}
+
+	cfg, _ := EnvironmentCatalog(local)
+	if got := cfg.Hosts[UrlPayment]; got != "https://apipayment.1099cloud.com/api/v1" {
+		t.Errorf("inherited payment host = %q, want the staging host", got)
+	}
+}
+
+func Test_tax1099Impl_endpointURL(t *testing.T) {
+	ta := &tax1099Impl{env: "unregistered"}
+
+	got, err := ta.endpointURL("tax1099.validate_1098")
+	if err != nil {
+		t.Fatalf("endpointURL() error = %v", err)
+	}
+	if want := "https://apiforms.1099cloud.com/api/v1/forms/1098/validate"; got != want {
+		t.Errorf("endpointURL() = %q, want %q for an unregistered environment", got, want)
+	}
+
+	if _, err := ta.endpointURL("tax1099.unknown"); !errors.Is(err, ErrUnknownEndpoint) {
+		t.Errorf("endpointURL() error = %v, want %v", err, ErrUnknownEndpoint)
+	}
+}
+
+func Test_tax1099Impl_endpointURL_UnknownProductionHost(t *testing.T) {
+	ops := []string{
+		"tax1099.validate_1099nec",
+		"tax1099.import_1099nec",
+		"tax1099.validate_1099int",
+		"tax1099.import_1099int",
+		"tax1099.validate_1099c",
+		"tax1099.import_1099c",
+		"tax1099.validate_1099a",
+		"tax1099.import_1099a",
+		"tax1099.validate_1099s",
+		"tax1099.import_1099s",
+		"tax1099.validate_1099misc",
+		"tax1099.import_1099misc",
+	}
+
+	ta := &tax1099Impl{env: EnvironmentProduction}
+	for _, op := range ops {
+		if got, err := ta.endpointURL(op); !errors.Is(err, ErrUnknownEndpoint) {
+			t.Errorf("endpointURL(%s) = %q, %v, want %v", op, got, err, ErrUnknownEndpoint)
+		}
+	}
+
+	ta.baseURLs = map[UrlType]string{"1099nec": "https://example.test/api/v1"}
+	got, err := ta.endpointURL("tax1099.validate_1099nec")
+	if err != nil {
+		t.Fatalf("endpointURL() error = %v", err)
+	}
+	if want := "https://example.test/api/v1/form/1099nec/validate"; got != want {
+		t.Errorf("endpointURL() = %q, want %q with a WithBaseURL override", got, want)
+	}
+}
+
+func Test_RegisterEnvironment_Builtin(t *testing.T) {
+	for _, env := range []Environment{EnvironmentStaging, EnvironmentProduction} {
+		err := RegisterEnvironment(env, EnvironmentConfig{Hosts: map[UrlType]string{UrlMain: "https://attacker.example/api/v1"}})
+		if !errors.Is(err, ErrBuiltinEnvironment) {
+			t.Errorf("RegisterEnvironment(%s) error = %v, want %v", env, err, ErrBuiltinEnvironment)
+		}
+
+		if got, _ := lookupHost(env, UrlMain); got == "https://attacker.example/api/v1" {
+			t.Errorf("RegisterEnvironment(%s) changed the main host", env)
+		}
+	}
+}
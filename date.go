@@ -0,0 +1,143 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned when text cannot be parsed as a Date or a Date is
+// not a real calendar day.
+var ErrInvalidDate = errors.New("invalid date")
+
+// dateFormat is the layout Tax1099 expects for dates.
+const dateFormat = "2006-01-02"
+
+// dateLayouts are the layouts ParseDate accepts, in the order they are tried.
+var dateLayouts = []string{
+	dateFormat,
+	"01/02/2006",
+	"1/2/2006",
+	"2006/01/02",
+	"20060102",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	time.RFC3339,
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+}
+
+// Date is a calendar day with no time of day or time zone, such as the date a
+// mortgage was originated. It marshals to JSON as "2006-01-02"; the zero Date
+// marshals as null and is left out of requests by the omitzero tag option,
+// which needs Go 1.24 or later.
+type Date struct {
+	Year  int        //Year is the four digit year
+	Month time.Month //Month is the month of the year
+	Day   int        //Day is the day of the month, starting at 1
+}
+
+// NewDate returns the date for year, month and day without normalizing it; use
+// IsValid to check it.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// DateOf returns the calendar day of t in t's own location. Convert t with
+// t.In first if it was recorded in a different zone than the one the date
+// belongs to, since the same instant can fall on different days.
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// ParseDate parses s in one of several common layouts, such as "2006-01-02",
+// "01/02/2006", "Jan 2, 2006" or an RFC 3339 timestamp, whose date is taken as
+// written. Slashed dates are read month first, as in the U.S.; dashed dates are
+// only accepted year first, since "04-05-2021" is ambiguous. An empty string
+// parses as the zero Date.
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Date{}, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return DateOf(t), nil
+		}
+	}
+
+	return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, s)
+}
+
+// IsZero reports whether d is the zero Date.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsValid reports whether d is a real calendar day.
+func (d Date) IsValid() bool {
+	return d.Year > 0 && d.Year <= 9999 && DateOf(d.Time(time.UTC)) == d
+}
+
+// Time returns midnight at the start of d in loc.
+func (d Date) Time(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Before reports whether d falls before other.
+func (d Date) Before(other Date) bool {
+	return d.Time(time.UTC).Before(other.Time(time.UTC))
+}
+
+// After reports whether d falls after other.
+func (d Date) After(other Date) bool {
+	return other.Before(d)
+}
+
+// String formats d as "2006-01-02", or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// MarshalJSON writes d as "2006-01-02", or null for the zero Date. A Date that
+// is not a real calendar day is an error rather than a date Tax1099 rejects.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+
+	if !d.IsValid() {
+		return nil, fmt.Errorf("%w: %04d-%02d-%02d", ErrInvalidDate, d.Year, d.Month, d.Day)
+	}
+
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON reads a date string in any layout ParseDate accepts, or null.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDate, data)
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,108 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_ParseDate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Date
+		wantErr bool
+	}{
+		{in: "2021-04-15", want: NewDate(2021, time.April, 15)},
+		{in: "04/15/2021", want: NewDate(2021, time.April, 15)},
+		{in: "4/5/2021", want: NewDate(2021, time.April, 5)},
+		{in: "2021/04/15", want: NewDate(2021, time.April, 15)},
+		{in: "20210415", want: NewDate(2021, time.April, 15)},
+		{in: "Apr 15, 2021", want: NewDate(2021, time.April, 15)},
+		{in: "2021-04-15T23:30:00-05:00", want: NewDate(2021, time.April, 15)},
+		{in: "", want: Date{}},
+		{in: "2021-02-30", wantErr: true},
+		{in: "15/04/2021", wantErr: true},
+		{in: "04-05-2021", wantErr: true},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidDate) {
+				t.Errorf("ParseDate(%q) error = %v, want %v", tt.in, err, ErrInvalidDate)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDate(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func Test_DateOf(t *testing.T) {
+	central := time.FixedZone("CST", -6*60*60)
+	instant := time.Date(2024, time.January, 1, 3, 0, 0, 0, time.UTC)
+
+	if got := DateOf(instant.In(central)); got != NewDate(2023, time.December, 31) {
+		t.Errorf("DateOf() = %v, want 2023-12-31 in the loan's zone", got)
+	}
+
+	if got := DateOf(instant); got != NewDate(2024, time.January, 1) {
+		t.Errorf("DateOf() = %v, want 2024-01-01 in UTC", got)
+	}
+}
+
+func Test_Date_JSON(t *testing.T) {
+	data, err := json.Marshal(Form1098{MortgageDate: NewDate(2021, time.April, 5)})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"mortgageDate":"2021-04-05"`) {
+		t.Errorf("Marshal() = %s, want mortgageDate 2021-04-05", data)
+	}
+
+	data, err = json.Marshal(Date{})
+	if err != nil || string(data) != "null" {
+		t.Errorf("Marshal(Date{}) = %s, %v, want null", data, err)
+	}
+
+	data, err = json.Marshal(Form1098{})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "mortgageDate") {
+		t.Errorf("Marshal() = %s, want the zero mortgageDate left out", data)
+	}
+
+	data, err = json.Marshal(Submit1098sRequest{TaxYear: "2024"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if strings.Contains(string(data), "scheduledDate") {
+		t.Errorf("Marshal() = %s, want the zero scheduledDate left out", data)
+	}
+
+	if _, err := json.Marshal(Form1098{MortgageDate: NewDate(2021, time.February, 30)}); !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("Marshal() error = %v, want %v", err, ErrInvalidDate)
+	}
+
+	var form Form1098
+	if err := json.Unmarshal([]byte(`{"mortgageDate":"04/05/2021"}`), &form); err != nil || form.MortgageDate != NewDate(2021, time.April, 5) {
+		t.Errorf("Unmarshal() = %v, %v, want 2021-04-05", form.MortgageDate, err)
+	}
+}
+
+func Test_Date_Compare(t *testing.T) {
+	a, b := NewDate(2024, time.March, 1), NewDate(2024, time.March, 2)
+
+	if !a.Before(b) || a.After(b) || !b.After(a) {
+		t.Errorf("Before/After disagree for %v and %v", a, b)
+	}
+
+	if (Date{}).String() != "" || a.String() != "2024-03-01" {
+		t.Errorf("String() = %q, %q", Date{}.String(), a.String())
+	}
+}
@@ -0,0 +1,93 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors matched by APIError through errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+	ErrServer       = errors.New("server error")
+)
+
+// APIError is returned when Tax1099 responds with a non-200 status code. When the
+// body contains Tax1099's JSON error envelope its fields are decoded into
+// Message, TraceIdentifier and ValidationErrors.
+//
+// The body can echo the TINs and contact details that were submitted. Error
+// runs the message or body it includes through Redact; the fields themselves
+// are left as received.
+type APIError struct {
+	StatusCode       int               //StatusCode is the HTTP status code of the response
+	Op               string            //Op is the library operation that made the request, e.g. "tax1099.import_1098"
+	URL              string            //URL is the endpoint that was called
+	Message          string            //Message is the error message from the envelope, if present
+	TraceIdentifier  string            //TraceIdentifier is Tax1099's request trace id, useful for support tickets
+	ValidationErrors []ValidationError //ValidationErrors lists field level problems reported by Tax1099
+	Body             []byte            //Body is the raw response body
+}
+
+// apiErrorEnvelope is the JSON error shape Tax1099 returns alongside non-200 responses.
+type apiErrorEnvelope struct {
+	Message          string            `json:"message"`
+	TraceIdentifier  string            `json:"traceIdentifier"`
+	ValidationErrors []ValidationError `json:"validationErrors"`
+}
+
+// newAPIError builds an APIError, decoding the JSON envelope when the body has one.
+func newAPIError(op, url string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Op:         op,
+		URL:        url,
+		Body:       body,
+	}
+
+	var envelope apiErrorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		apiErr.Message = envelope.Message
+		apiErr.TraceIdentifier = envelope.TraceIdentifier
+		apiErr.ValidationErrors = envelope.ValidationErrors
+	}
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s: status code %d returned from %s", e.Op, e.StatusCode, e.URL)
+
+	switch {
+	case e.Message != "":
+		msg += ": " + Redact(e.Message)
+	case len(e.Body) > 0:
+		msg += " with body: " + truncateForError(e.Body)
+	}
+
+	if e.TraceIdentifier != "" {
+		msg += fmt.Sprintf(" (trace %s)", e.TraceIdentifier)
+	}
+
+	return msg
+}
+
+// Is reports whether the error belongs to the class described by target, so
+// callers can write errors.Is(err, ErrUnauthorized) and similar.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity || len(e.ValidationErrors) > 0
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+
+	return false
+}
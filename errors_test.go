@@ -0,0 +1,105 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func Test_APIError_Is(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       []error
+		wantNot    []error
+	}{
+		{
+			name:       "401 is unauthorized",
+			statusCode: http.StatusUnauthorized,
+			want:       []error{ErrUnauthorized},
+			wantNot:    []error{ErrRateLimited, ErrValidation, ErrServer},
+		},
+		{
+			name:       "429 is rate limited",
+			statusCode: http.StatusTooManyRequests,
+			want:       []error{ErrRateLimited},
+			wantNot:    []error{ErrUnauthorized, ErrValidation, ErrServer},
+		},
+		{
+			name:       "422 is validation",
+			statusCode: http.StatusUnprocessableEntity,
+			want:       []error{ErrValidation},
+			wantNot:    []error{ErrUnauthorized, ErrRateLimited, ErrServer},
+		},
+		{
+			name:       "validation errors in the envelope are validation",
+			statusCode: http.StatusConflict,
+			body:       `{"validationErrors":[{"field":"payerTin","source":"payerInfo","message":"required"}]}`,
+			want:       []error{ErrValidation},
+			wantNot:    []error{ErrServer},
+		},
+		{
+			name:       "502 is server",
+			statusCode: http.StatusBadGateway,
+			want:       []error{ErrServer},
+			wantNot:    []error{ErrUnauthorized, ErrRateLimited, ErrValidation},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := error(newAPIError("tax1099.test", "http://example.test", tt.statusCode, []byte(tt.body)))
+
+			for _, target := range tt.want {
+				if !errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, target)
+				}
+			}
+
+			for _, target := range tt.wantNot {
+				if errors.Is(err, target) {
+					t.Errorf("errors.Is(%v, %v) = true, want false", err, target)
+				}
+			}
+		})
+	}
+}
+
+func Test_tax1099Impl_post_ReturnsAPIError(t *testing.T) {
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"One or more validation errors occurred.","traceIdentifier":"00-abc-01","validationErrors":[{"field":"recipientTin","source":"forms[0]","message":"invalid"}]}`))
+	})
+
+	ta := &tax1099Impl{
+		env:            EnvironmentStaging,
+		token:          "test-token",
+		tokenExpiresAt: time.Now().Add(1 * time.Hour),
+		client:         server.Client(),
+	}
+
+	err := ta.post(context.Background(), "tax1099.import_1098", server.URL+"/api/v1/forms/importonly/1098", Submit1098Request{}, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("post() error = %v, want *APIError", err)
+	}
+
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("StatusCode = %d, want %d", apiErr.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if apiErr.Op != "tax1099.import_1098" {
+		t.Errorf("Op = %q, want %q", apiErr.Op, "tax1099.import_1098")
+	}
+	if apiErr.TraceIdentifier != "00-abc-01" {
+		t.Errorf("TraceIdentifier = %q, want %q", apiErr.TraceIdentifier, "00-abc-01")
+	}
+	if len(apiErr.ValidationErrors) != 1 || apiErr.ValidationErrors[0].Field != "recipientTin" {
+		t.Errorf("ValidationErrors = %+v, want one recipientTin error", apiErr.ValidationErrors)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("errors.Is(err, ErrValidation) = false, want true")
+	}
+}
@@ -0,0 +1,254 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+)
+
+// ErrUnknownForm is returned by Validate, Import and Submit for a form type
+// that has not been registered with RegisterForm.
+var ErrUnknownForm = errors.New("form type is not registered")
+
+// Form is implemented by every form type that can be validated, imported and
+// submitted through the generic functions. The payer is not part of the form;
+// it is carried by the Item the form is grouped under.
+type Form interface {
+	FormName() string         //FormName is the name the form type was registered under, e.g. "1099-NEC"
+	FormTaxYear() TaxYear     //FormTaxYear is the year for which the form is being filed
+	Recipient() RecipientInfo //Recipient is the recipient, borrower or debtor the form is issued to
+}
+
+// FormSpec describes where a registered form type is served.
+type FormSpec struct {
+	Name     string                 //Name is the value returned by Form.FormName and the default formName on submit
+	Segment  string                 //Segment is the form's part of endpoint paths, e.g. "1099nec" in "forms/1099nec/validate"
+	UrlType  UrlType                //UrlType is the host that serves validate and import, UrlType(Segment) if empty; WithBaseURL overrides it
+	BaseURLs map[Environment]string //BaseURLs is the host's base URL, including the API version path, per environment; leave out environments whose host is not confirmed, and their requests fail with ErrUnknownEndpoint until WithBaseURL supplies it
+}
+
+// Item represents a single payer and their associated forms
+type Item[F Form] struct {
+	PayerInfo PayerInfo `json:"payerInfo"`
+	Forms     []F       `json:"forms"`
+}
+
+// SubmitRequest represents the JSON structure for validating or importing forms
+type SubmitRequest[F Form] struct {
+	TaxYear TaxYear   `json:"taxYear"`
+	Items   []Item[F] `json:"items"`
+}
+
+// PaymentRequest represents the JSON structure for submitting forms for filing
+type PaymentRequest[F Form] struct {
+	TaxYear         TaxYear   `json:"taxYear"`
+	FormName        string    `json:"formName"`
+	ScheduledDate   Date      `json:"scheduledDate,omitzero"`
+	IsCorrected     bool      `json:"isCorrected"`
+	CouponCode      string    `json:"couponCode"`
+	CardReferenceID string    `json:"cardReferenceId"`
+	Items           []Item[F] `json:"items"`
+}
+
+var formRegistry = struct {
+	sync.RWMutex
+	specs       map[string]FormSpec
+	validateOps map[string]bool
+}{specs: map[string]FormSpec{}, validateOps: map[string]bool{}}
+
+// RegisterForm makes a form type available to Validate, Import and Submit and
+// adds its endpoints to the catalog. Its validate operation is retried like any
+// other idempotent request; see RetryPolicy.
+//
+// RegisterForm is meant to be called from an init function. It panics if spec
+// has no Name or Segment, or if another form type was already registered under
+// the same Name or Segment.
+func RegisterForm(spec FormSpec) {
+	if spec.Name == "" || spec.Segment == "" {
+		panic("tax1099: RegisterForm needs a Name and Segment")
+	}
+
+	if spec.UrlType == "" {
+		spec.UrlType = UrlType(spec.Segment)
+	}
+
+	formRegistry.Lock()
+	defer formRegistry.Unlock()
+
+	if _, ok := formRegistry.specs[spec.Name]; ok {
+		panic("tax1099: RegisterForm called twice for form " + spec.Name)
+	}
+
+	for _, other := range formRegistry.specs {
+		if other.Segment == spec.Segment {
+			panic("tax1099: RegisterForm called for " + spec.Name + " with the segment of form " + other.Name)
+		}
+	}
+
+	formRegistry.specs[spec.Name] = spec
+	formRegistry.validateOps[formOp("validate", spec)] = true
+	registerFormEndpoints(spec)
+}
+
+// formOp returns the operation name of a form endpoint, e.g.
+// "tax1099.validate_1099nec" or "tax1099.submit_1099necs".
+func formOp(action string, spec FormSpec) string {
+	if action == "submit" {
+		return "tax1099.submit_" + spec.Segment + "s"
+	}
+
+	return "tax1099." + action + "_" + spec.Segment
+}
+
+// isFormValidateOp reports whether op validates a registered form type.
+func isFormValidateOp(op string) bool {
+	formRegistry.RLock()
+	defer formRegistry.RUnlock()
+
+	return formRegistry.validateOps[op]
+}
+
+// LookupForm returns the spec registered under name.
+func LookupForm(name string) (FormSpec, bool) {
+	formRegistry.RLock()
+	defer formRegistry.RUnlock()
+
+	spec, ok := formRegistry.specs[name]
+	return spec, ok
+}
+
+// RegisteredForms returns the specs of all registered form types sorted by name.
+func RegisteredForms() []FormSpec {
+	formRegistry.RLock()
+	defer formRegistry.RUnlock()
+
+	specs := make([]FormSpec, 0, len(formRegistry.specs))
+	for _, spec := range formRegistry.specs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+
+	return specs
+}
+
+// specFor returns the registered spec of the form type F.
+func specFor[F Form]() (FormSpec, error) {
+	var form F
+	spec, ok := LookupForm(form.FormName())
+	if !ok {
+		return FormSpec{}, fmt.Errorf("%s: %w", form.FormName(), ErrUnknownForm)
+	}
+
+	return spec, nil
+}
+
+// FormCall is one request made by Validate, Import or Submit.
+type FormCall struct {
+	Op      string  //Op names the operation, e.g. "tax1099.validate_1099nec"; see EnvironmentCatalog for its endpoint
+	Form    string  //Form is the name of the form type being sent
+	Action  string  //Action is "validate", "import" or "submit"
+	TaxYear TaxYear //TaxYear is the tax year of the request
+	Payload any     //Payload is a pointer to the request, sent as JSON
+}
+
+// FormSender is the part of Tax1099 used by Validate, Import and Submit, so a
+// type wrapping or mocking a Tax1099 can take part in the generic functions.
+type FormSender interface {
+	// SendForm checks call.TaxYear against the client's TaxYearWindow, posts
+	// call.Payload to the endpoint of call.Op and decodes the response into res.
+	SendForm(ctx context.Context, call FormCall, res any) error
+}
+
+// SendForm implements the request behind Validate, Import and Submit.
+func (t *tax1099Impl) SendForm(ctx context.Context, call FormCall, res any) error {
+	if !t.skipTaxYearCheck {
+		if err := t.taxYearWindow.Check(call.TaxYear, t.now()); err != nil {
+			return err
+		}
+	} else if !call.TaxYear.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTaxYear, string(call.TaxYear))
+	}
+
+	t.log().InfoContext(ctx, fmt.Sprintf("Sending the %s forms to %s...", call.Form, call.Action),
+		slog.String("component", component),
+		slog.String("op", call.Op),
+	)
+
+	url, err := t.endpointURL(call.Op)
+	if err != nil {
+		return err
+	}
+
+	if err := t.post(ctx, call.Op, url, call.Payload, res); err != nil {
+		return err
+	}
+
+	t.log().InfoContext(ctx, fmt.Sprintf("...%s forms sent to %s", call.Form, call.Action),
+		slog.String("component", component),
+		slog.String("op", call.Op),
+		slog.Any("response", res),
+	)
+
+	return nil
+}
+
+// sendForm fills in the tax year of a request for F, checks its forms agree
+// with it and hands the request to client. taxYear points into payload.
+func sendForm[F Form](ctx context.Context, client Tax1099, action string, taxYear *TaxYear, items []Item[F], payload, res any) error {
+	spec, err := specFor[F]()
+	if err != nil {
+		return err
+	}
+
+	year, err := requestTaxYear(*taxYear, items)
+	if err != nil {
+		return err
+	}
+	*taxYear = year
+
+	return client.SendForm(ctx, FormCall{
+		Op:      formOp(action, spec),
+		Form:    spec.Name,
+		Action:  action,
+		TaxYear: year,
+		Payload: payload,
+	}, res)
+}
+
+// Validate submits the forms in payload for validation without saving them.
+//
+// Like Import and Submit, it first works out the request's tax year, taking it
+// from the forms when payload.TaxYear is empty, and checks that every form is
+// for that year and that the client's TaxYearWindow accepts it.
+func Validate[F Form](ctx context.Context, client Tax1099, payload SubmitRequest[F]) (FormResponse, error) {
+	var res FormResponse
+	err := sendForm(ctx, client, "validate", &payload.TaxYear, payload.Items, &payload, &res)
+
+	return res, err
+}
+
+// Import saves the forms in payload in Tax1099 without filing them.
+func Import[F Form](ctx context.Context, client Tax1099, payload SubmitRequest[F]) (FormResponse, error) {
+	var res FormResponse
+	err := sendForm(ctx, client, "import", &payload.TaxYear, payload.Items, &payload, &res)
+
+	return res, err
+}
+
+// Submit imports the forms in payload and submits them for filing through the
+// payment API. An empty FormName is filled in from the form's registration.
+func Submit[F Form](ctx context.Context, client Tax1099, payload PaymentRequest[F]) (PaymentResponse, error) {
+	if payload.FormName == "" {
+		if spec, err := specFor[F](); err == nil {
+			payload.FormName = spec.Name
+		}
+	}
+
+	var res PaymentResponse
+	err := sendForm(ctx, client, "submit", &payload.TaxYear, payload.Items, &payload, &res)
+
+	return res, err
+}
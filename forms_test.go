@@ -0,0 +1,359 @@
+package tax1099
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testForm is a form type registered only for these tests, showing what a
+// caller needs to add a form the library does not ship.
+type testForm struct {
+	RecipientInfo RecipientInfo `json:"recipientInfo"`
+	TaxYear       TaxYear       `json:"taxYear"`
+	Amount        float64       `json:"amount"`
+}
+
+func (testForm) FormName() string           { return "1099-TEST" }
+func (f testForm) FormTaxYear() TaxYear     { return f.TaxYear }
+func (f testForm) Recipient() RecipientInfo { return f.RecipientInfo }
+
+// unregisteredForm is never registered.
+type unregisteredForm struct{ testForm }
+
+func (unregisteredForm) FormName() string { return "1099-UNREGISTERED" }
+
+const urlTestForm UrlType = "1099test"
+
+func init() {
+	RegisterForm(FormSpec{Name: "1099-TEST", Segment: "1099test", UrlType: urlTestForm})
+}
+
+func Test_GenericForms(t *testing.T) {
+	var gotPath, gotFormName string
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+
+		var body struct {
+			FormName string `json:"formName"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotFormName = body.FormName
+
+		w.Write([]byte(`{"totalCount":1}`))
+	})
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithBaseURL(urlTestForm, server.URL+"/api/v1"),
+		WithBaseURL(UrlPayment, server.URL+"/api/v1"),
+		WithClock(filingSeason),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	request := SubmitRequest[testForm]{TaxYear: "2024", Items: []Item[testForm]{{Forms: []testForm{{TaxYear: "2024", Amount: 10}}}}}
+
+	tests := []struct {
+		name         string
+		call         func() error
+		wantPath     string
+		wantFormName string
+	}{
+		{
+			name: "validate",
+			call: func() error {
+				_, err := Validate(context.Background(), client, request)
+				return err
+			},
+			wantPath: "/api/v1/forms/1099test/validate",
+		},
+		{
+			name: "import",
+			call: func() error {
+				_, err := Import(context.Background(), client, request)
+				return err
+			},
+			wantPath: "/api/v1/forms/importonly/1099test",
+		},
+		{
+			name: "submit fills in the form name",
+			call: func() error {
+				_, err := Submit(context.Background(), client, PaymentRequest[testForm]{TaxYear: "2024", Items: request.Items})
+				return err
+			},
+			wantPath:     "/api/v1/payment/forms/import/submit/1099test",
+			wantFormName: "1099-TEST",
+		},
+		{
+			name: "submit keeps a form name that was set",
+			call: func() error {
+				_, err := Submit(context.Background(), client, PaymentRequest[testForm]{TaxYear: "2024", FormName: "custom", Items: request.Items})
+				return err
+			},
+			wantPath:     "/api/v1/payment/forms/import/submit/1099test",
+			wantFormName: "custom",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPath, gotFormName = "", ""
+
+			if err := tt.call(); err != nil {
+				t.Fatalf("error = %v, want nil", err)
+			}
+
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %q, want %q", gotPath, tt.wantPath)
+			}
+
+			if gotFormName != tt.wantFormName {
+				t.Errorf("formName = %q, want %q", gotFormName, tt.wantFormName)
+			}
+		})
+	}
+}
+
+func Test_GenericForms_Errors(t *testing.T) {
+	client := &tax1099Impl{}
+
+	_, err := Validate(context.Background(), client, SubmitRequest[unregisteredForm]{})
+	if !errors.Is(err, ErrUnknownForm) {
+		t.Errorf("Validate() error = %v, want %v", err, ErrUnknownForm)
+	}
+
+}
+
+// wrappedClient stands in for a caller's own Tax1099 wrapper or mock.
+type wrappedClient struct {
+	Tax1099
+	calls []FormCall
+}
+
+func (c *wrappedClient) SendForm(ctx context.Context, call FormCall, res any) error {
+	c.calls = append(c.calls, call)
+	return c.Tax1099.SendForm(ctx, call, res)
+}
+
+func Test_GenericForms_WrappedClient(t *testing.T) {
+	client, got := newFormTestClient(t)
+	wrapped := &wrappedClient{Tax1099: client}
+
+	request := SubmitRequest[testForm]{TaxYear: "2024", Items: []Item[testForm]{{Forms: []testForm{{Amount: 10}}}}}
+	if _, err := Import(context.Background(), wrapped, request); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	want := []FormCall{{Op: "tax1099.import_1099test", Form: "1099-TEST", Action: "import", TaxYear: "2024", Payload: &request}}
+	if !reflect.DeepEqual(wrapped.calls, want) {
+		t.Errorf("calls = %+v, want %+v", wrapped.calls, want)
+	}
+
+	if got.Path != "/api/v1/forms/importonly/1099test" {
+		t.Errorf("path = %q, want the wrapped client's request", got.Path)
+	}
+}
+
+// formCalls returns the validate, import and submit calls of a form type, each
+// sending items for 2024.
+func formCalls[F Form](items []Item[F]) map[string]func(client Tax1099) error {
+	ctx := context.Background()
+
+	return map[string]func(client Tax1099) error{
+		"validate": func(client Tax1099) error {
+			_, err := Validate(ctx, client, SubmitRequest[F]{TaxYear: "2024", Items: items})
+			return err
+		},
+		"import": func(client Tax1099) error {
+			_, err := Import(ctx, client, SubmitRequest[F]{TaxYear: "2024", Items: items})
+			return err
+		},
+		"submit": func(client Tax1099) error {
+			_, err := Submit(ctx, client, PaymentRequest[F]{TaxYear: "2024", Items: items})
+			return err
+		},
+	}
+}
+
+func Test_RegisteredForms(t *testing.T) {
+	payer := PayerInfo{TinType: TinTypeBusiness, TaxIdentifer: "123456789", LastNameOrBusinessName: "Lender LLC"}
+	recipient := RecipientInfo{TinType: TinTypeIndividual, TaxIdentifer: "987654321", LastNameOrBusinessName: "Recipient"}
+
+	tests := []struct {
+		segment  string
+		calls    map[string]func(client Tax1099) error
+		wantForm map[string]any
+	}{
+		{
+			segment: "1099nec",
+			calls: formCalls([]Item1099NEC{{PayerInfo: payer, Forms: []Form1099NEC{{
+				RecipientInfo:            recipient,
+				TaxYear:                  "2024",
+				NonemployeeCompensation:  MoneyFromCents(1250050),
+				FederalIncomeTaxWithheld: MoneyFromCents(30000),
+				States:                   []StateWithholding{{State: "TX", PayerStateNo: "12345", StateIncome: MoneyFromCents(1250050), StateTaxWithheld: MoneyFromCents(2500)}},
+			}}}}),
+			wantForm: map[string]any{
+				"nonEmployeeCompensation":  12500.50,
+				"federalIncomeTaxWithheld": 300.0,
+			},
+		},
+		{
+			segment: "1099int",
+			calls: formCalls([]Item1099INT{{PayerInfo: payer, Forms: []Form1099INT{{
+				RecipientInfo:          recipient,
+				TaxYear:                "2024",
+				InterestIncome:         MoneyFromCents(84217),
+				EarlyWithdrawalPenalty: MoneyFromCents(1000),
+				TaxExemptInterest:      MoneyFromCents(5550),
+				BondPremium:            MoneyFromCents(325),
+			}}}}),
+			wantForm: map[string]any{
+				"interestIncome":         842.17,
+				"earlyWithdrawalPenalty": 10.0,
+				"taxExemptInterest":      55.5,
+				"bondPremium":            3.25,
+			},
+		},
+		{
+			segment: "1099c",
+			calls: formCalls([]Item1099C{{PayerInfo: payer, Forms: []Form1099C{{
+				RecipientInfo:    recipient,
+				TaxYear:          "2024",
+				EventDate:        NewDate(2024, 6, 30),
+				DebtDischarged:   MoneyFromCents(4125075),
+				InterestIncluded: MoneyFromCents(125075),
+				DebtDescription:  "Land loan 1001",
+				PersonallyLiable: true,
+				EventCode:        EventCodeAgreement,
+				FairMarketValue:  MoneyFromCents(3000000),
+			}}}}),
+			wantForm: map[string]any{
+				"dateOfIdentifiableEvent": "2024-06-30",
+				"amountOfDebtDischarged":  41250.75,
+				"interestIfIncluded":      1250.75,
+				"personallyLiable":        true,
+				"identifiableEventCode":   "F",
+				"fairMarketValue":         30000.0,
+			},
+		},
+		{
+			segment: "1099a",
+			calls: formCalls([]Item1099A{{PayerInfo: payer, Forms: []Form1099A{{
+				RecipientInfo:       recipient,
+				TaxYear:             "2024",
+				AcquisitionDate:     NewDate(2024, 3, 15),
+				PrincipalBalance:    MoneyFromCents(5800000),
+				FairMarketValue:     MoneyFromCents(6100000),
+				PersonallyLiable:    true,
+				PropertyDescription: "Lot 12, Block 4",
+			}}}}),
+			wantForm: map[string]any{
+				"dateOfAcquisition":    "2024-03-15",
+				"principalOutstanding": 58000.0,
+				"fairMarketValue":      61000.0,
+				"personallyLiable":     true,
+				"propertyDescription":  "Lot 12, Block 4",
+			},
+		},
+		{
+			segment: "1099s",
+			calls: formCalls([]Item1099S{{PayerInfo: payer, Forms: []Form1099S{{
+				RecipientInfo:       recipient,
+				TaxYear:             "2024",
+				ClosingDate:         NewDate(2024, 9, 20),
+				GrossProceeds:       MoneyFromCents(14500000),
+				PropertyAddress:     "Tract 7, Smith County",
+				ForeignTransferor:   true,
+				BuyersRealEstateTax: MoneyFromCents(81240),
+			}}}}),
+			wantForm: map[string]any{
+				"dateOfClosing":       "2024-09-20",
+				"grossProceeds":       145000.0,
+				"propertyAddress":     "Tract 7, Smith County",
+				"foreignTransferor":   true,
+				"buyersRealEstateTax": 812.4,
+			},
+		},
+		{
+			segment: "1099misc",
+			calls: formCalls([]Item1099MISC{{PayerInfo: payer, Forms: []Form1099MISC{{
+				RecipientInfo:            recipient,
+				TaxYear:                  "2024",
+				Rents:                    MoneyFromCents(3600000),
+				OtherIncome:              MoneyFromCents(15025),
+				AttorneyProceeds:         MoneyFromCents(250000),
+				NonqualifiedDeferredComp: MoneyFromCents(120000),
+				States:                   []StateWithholding{{State: "TX", StateIncome: MoneyFromCents(3600000)}},
+			}}}}),
+			wantForm: map[string]any{
+				"rents":                    36000.0,
+				"otherIncome":              150.25,
+				"attorneyProceeds":         2500.0,
+				"nonqualifiedDeferredComp": 1200.0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		wantPaths := map[string]string{
+			"validate": "/api/v1/forms/" + tt.segment + "/validate",
+			"import":   "/api/v1/forms/importonly/" + tt.segment,
+			"submit":   "/api/v1/payment/forms/import/submit/" + tt.segment,
+		}
+
+		for action, call := range tt.calls {
+			t.Run(tt.segment+"/"+action, func(t *testing.T) {
+				client, got := newFormTestClient(t)
+
+				if err := call(client); err != nil {
+					t.Fatalf("error = %v, want nil", err)
+				}
+
+				if got.Path != wantPaths[action] {
+					t.Errorf("path = %q, want %q", got.Path, wantPaths[action])
+				}
+
+				for field, value := range tt.wantForm {
+					if got.Form[field] != value {
+						t.Errorf("%s = %v, want %v", field, got.Form[field], value)
+					}
+				}
+			})
+		}
+	}
+
+	for _, spec := range RegisteredForms() {
+		if !RetryPolicy.allows(DefaultRetryPolicy, formOp("validate", spec)) {
+			t.Errorf("validate of %s is not retried", spec.Name)
+		}
+
+		if RetryPolicy.allows(DefaultRetryPolicy, formOp("import", spec)) {
+			t.Errorf("import of %s is retried", spec.Name)
+		}
+	}
+}
+
+func Test_RegisterForm_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterForm() did not panic for a duplicate name")
+		}
+	}()
+
+	RegisterForm(FormSpec{Name: "1099-NEC", Segment: "1099nec"})
+}
+
+func Test_RegisterForm_DuplicateSegment(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RegisterForm() did not panic for a duplicate segment")
+		}
+	}()
+
+	RegisterForm(FormSpec{Name: "1099-NEC copy", Segment: "1099nec"})
+}
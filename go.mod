@@ -1,3 +1,3 @@
 module github.com/Lendiom/go-tax1099
 
-go 1.21.5
+go 1.24
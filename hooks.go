@@ -0,0 +1,42 @@
+package tax1099
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// BeforeRequestFunc runs before every HTTP attempt, including retries and the
+// replay after a re-authorization, for every endpoint. It may add headers to
+// req; returning an error aborts the call with that error.
+type BeforeRequestFunc func(ctx context.Context, op string, req *http.Request) error
+
+// AfterResponseFunc runs after every HTTP attempt with the outcome of that
+// attempt. It must not read or close info.Response.Body.
+type AfterResponseFunc func(ctx context.Context, info ResponseInfo)
+
+// ResponseInfo describes a single HTTP attempt.
+type ResponseInfo struct {
+	Op       string         //Op is the library operation, e.g. "tax1099.import_1098"
+	Attempt  int            //Attempt counts from 1 and increases with each retry
+	Request  *http.Request  //Request is the request that was sent
+	Response *http.Response //Response is nil when Err is set
+	Duration time.Duration  //Duration is how long the attempt took to return headers
+	Err      error          //Err is the transport error, if any
+}
+
+// WithBeforeRequest appends hook to the chain run before each request. Hooks run
+// in the order they were added.
+func WithBeforeRequest(hook BeforeRequestFunc) Option {
+	return func(t *tax1099Impl) {
+		t.beforeRequest = append(t.beforeRequest, hook)
+	}
+}
+
+// WithAfterResponse appends hook to the chain run after each response. Hooks run
+// in the order they were added.
+func WithAfterResponse(hook AfterResponseFunc) Option {
+	return func(t *tax1099Impl) {
+		t.afterResponse = append(t.afterResponse, hook)
+	}
+}
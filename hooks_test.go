@@ -0,0 +1,93 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_Hooks_RunForEveryAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Request-Source"); got != "batch" {
+			t.Errorf("X-Request-Source header = %q, want %q", got, "batch")
+		}
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("%PDF-1.4 mock pdf content"))
+	})
+
+	var infos []ResponseInfo
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		WithoutEagerAuth(),
+		WithBeforeRequest(func(ctx context.Context, op string, req *http.Request) error {
+			req.Header.Set("X-Request-Source", "batch")
+			return nil
+		}),
+		WithAfterResponse(func(ctx context.Context, info ResponseInfo) {
+			infos = append(infos, info)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if _, err := client.DownloadFilledForm(context.Background(), DownloadFormRequest{FormID: 1, FormType: "1098"}); err != nil {
+		t.Fatalf("DownloadFilledForm() error = %v", err)
+	}
+
+	want := []struct {
+		op         string
+		attempt    int
+		statusCode int
+	}{
+		{"tax1099.authorize", 1, http.StatusOK},
+		{"tax1099.download_filled_form", 1, http.StatusBadGateway},
+		{"tax1099.download_filled_form", 2, http.StatusOK},
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("AfterResponse calls = %d, want %d", len(infos), len(want))
+	}
+	for i, w := range want {
+		got := infos[i]
+		if got.Op != w.op || got.Attempt != w.attempt || got.Response == nil || got.Response.StatusCode != w.statusCode {
+			t.Errorf("AfterResponse call %d = op %q attempt %d, want op %q attempt %d status %d", i, got.Op, got.Attempt, w.op, w.attempt, w.statusCode)
+		}
+	}
+}
+
+func Test_Hooks_BeforeRequestErrorAborts(t *testing.T) {
+	var requests atomic.Int32
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	})
+
+	errBlocked := errors.New("blocked by policy")
+	ta := &tax1099Impl{
+		env:            EnvironmentStaging,
+		token:          "test-token",
+		tokenExpiresAt: time.Now().Add(1 * time.Hour),
+		client:         server.Client(),
+		retryPolicy:    DefaultRetryPolicy,
+	}
+	WithBeforeRequest(func(ctx context.Context, op string, req *http.Request) error {
+		return errBlocked
+	})(ta)
+
+	_, err := ta.postForBytes(context.Background(), "tax1099.download_filled_form", server.URL+"/api/v1/pdf/forms/getpdfs", DownloadFormRequest{FormID: 1, FormType: "1098"})
+	if !errors.Is(err, errBlocked) {
+		t.Fatalf("postForBytes() error = %v, want %v", err, errBlocked)
+	}
+
+	if got := requests.Load(); got != 0 {
+		t.Errorf("requests sent = %d, want 0", got)
+	}
+}
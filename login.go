@@ -9,6 +9,10 @@ import (
 
 var ErrBadLogin = errors.New("bad login")
 
+// authorizeOp names the login endpoint. Requests to it skip re-authorization,
+// whatever path the environment maps it to.
+const authorizeOp = "tax1099.authorize"
+
 type loginRequest struct {
 	Email    string `json:"login"`
 	Password string `json:"password"`
@@ -21,15 +25,31 @@ type loginResponse struct {
 }
 
 func (t *tax1099Impl) Authorize(ctx context.Context, email, password, appKey string) error {
-	const op = "tax1099.authorize"
+	const op = authorizeOp
 
-	slog.InfoContext(ctx, "Authorizing...",
+	t.log().InfoContext(ctx, "Authorizing...",
 		slog.String("component", component),
 		slog.String("op", op),
 	)
 
+	loginURL, err := t.endpointURL(op)
+	if err != nil {
+		return err
+	}
+
+	storeKey := tokenStoreKey(loginURL, email, appKey)
+
+	if t.loadStoredToken(ctx, op, storeKey) {
+		t.log().InfoContext(ctx, "...reusing stored session",
+			slog.String("component", component),
+			slog.String("op", op),
+		)
+
+		return nil
+	}
+
 	var res loginResponse
-	if err := t.post(ctx, op, t.generateFullUrl(UrlMain, "login"), loginRequest{Email: email, Password: password, AppKey: appKey}, &res); err != nil {
+	if err := t.post(ctx, op, loginURL, loginRequest{Email: email, Password: password, AppKey: appKey}, &res); err != nil {
 		return err
 	}
 
@@ -37,13 +57,135 @@ func (t *tax1099Impl) Authorize(ctx context.Context, email, password, appKey str
 		return ErrBadLogin
 	}
 
+	t.mu.Lock()
 	t.token = res.SessionID
-	t.tokenExpiresAt = time.Now().Add(55 * time.Minute) // 5 minutes before the token expires
+	t.tokenExpiresAt = t.now().Add(55 * time.Minute) // 5 minutes before the token expires
+	token := Token{SessionID: t.token, ExpiresAt: t.tokenExpiresAt}
+	t.mu.Unlock()
 
-	slog.InfoContext(ctx, "...authorization complete",
+	if t.tokenStore != nil {
+		if err := t.tokenStore.Save(ctx, storeKey, token); err != nil {
+			t.log().WarnContext(ctx, "Failed to save session to token store",
+				slog.String("component", component),
+				slog.String("op", op),
+				slog.Any("error", err),
+			)
+		}
+	}
+
+	t.log().InfoContext(ctx, "...authorization complete",
 		slog.String("component", component),
 		slog.String("op", op),
 	)
 
 	return nil
 }
+
+// loadStoredToken adopts an unexpired session from the token store, skipping one
+// Tax1099 has already rejected. Store failures are logged and treated as a miss
+// so a broken store never prevents logging in.
+func (t *tax1099Impl) loadStoredToken(ctx context.Context, op, key string) bool {
+	if t.tokenStore == nil {
+		return false
+	}
+
+	token, ok, err := t.tokenStore.Load(ctx, key)
+	if err != nil {
+		t.log().WarnContext(ctx, "Failed to load session from token store",
+			slog.String("component", component),
+			slog.String("op", op),
+			slog.Any("error", err),
+		)
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !ok || token.SessionID == "" || token.SessionID == t.rejectedToken || !t.now().Before(token.ExpiresAt) {
+		return false
+	}
+
+	t.token = token.SessionID
+	t.tokenExpiresAt = token.ExpiresAt
+
+	return true
+}
+
+// authCall is an in-flight re-authorization shared by every caller that finds
+// the token expired while it runs.
+type authCall struct {
+	done chan struct{}
+	err  error
+}
+
+// sharedLoginTimeout bounds a login shared by ensureToken callers, which no
+// single caller's context can cancel.
+const sharedLoginTimeout = time.Minute
+
+// ensureToken re-authorizes when the token has expired. Concurrent callers
+// share a single login: the first one starts it and every caller, including
+// the first, waits for its result or for its own context to end. The login
+// runs on a context detached from the caller that started it, so one caller
+// giving up does not fail the others.
+func (t *tax1099Impl) ensureToken(ctx context.Context) error {
+	t.mu.Lock()
+	if !t.now().After(t.tokenExpiresAt) {
+		t.mu.Unlock()
+		return nil
+	}
+
+	if call := t.refresh; call != nil {
+		t.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	call := &authCall{done: make(chan struct{})}
+	t.refresh = call
+	t.mu.Unlock()
+
+	go func() {
+		loginCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLoginTimeout)
+		defer cancel()
+
+		call.err = t.Authorize(loginCtx, t.username, t.password, t.appKey)
+
+		t.mu.Lock()
+		t.refresh = nil
+		t.mu.Unlock()
+		close(call.done)
+	}()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// expireToken marks the token as expired so the next ensureToken logs in again,
+// and remembers it so a token store cannot hand it back. It does nothing if another caller has already replaced the rejected token.
+func (t *tax1099Impl) expireToken(rejected string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token == rejected {
+		t.tokenExpiresAt = time.Time{}
+		t.rejectedToken = rejected
+	}
+}
+
+// currentToken returns the session token to send with a request.
+func (t *tax1099Impl) currentToken() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.token
+}
@@ -0,0 +1,300 @@
+package tax1099
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_tax1099Impl_ConcurrentRequestsShareOneLogin(t *testing.T) {
+	const workers = 20
+
+	var logins atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			if logins.Add(1) == 1 {
+				close(started)
+			}
+			// Hold the login open so workers arriving meanwhile queue up behind it.
+			<-release
+			json.NewEncoder(w).Encode(loginResponse{SessionID: "test-token"})
+			return
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer test-token" {
+			t.Errorf("Authorization header = %q, want %q", got, "Bearer test-token")
+		}
+		w.Write([]byte(`{"totalCount":1}`))
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", 5*time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithBaseURL(Url1098, server.URL+"/api/v1"),
+		WithClock(clock),
+		WithoutEagerAuth(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2023"})
+			errs <- err
+		}()
+	}
+
+	// Workers that find the token expired before the login finishes wait for it;
+	// any arriving later find the new token, so either way one login serves all.
+	<-started
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Import1098() error = %v", err)
+		}
+	}
+
+	if got := logins.Load(); got != 1 {
+		t.Errorf("login calls = %d, want 1", got)
+	}
+}
+
+func Test_tax1099Impl_SharedLoginOutlivesCanceledCaller(t *testing.T) {
+	var logins atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			if logins.Add(1) == 1 {
+				close(started)
+			}
+			<-release
+			json.NewEncoder(w).Encode(loginResponse{SessionID: "test-token"})
+			return
+		}
+
+		w.Write([]byte(`{"totalCount":1}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", 5*time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithBaseURL(Url1098, server.URL+"/api/v1"),
+		WithClock(filingSeason),
+		WithoutEagerAuth(),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.Import1098(leaderCtx, Submit1098Request{TaxYear: "2024"})
+		leaderErr <- err
+	}()
+
+	<-started
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("leader Import1098() error = %v, want %v", err, context.Canceled)
+	}
+
+	// Had the cancel aborted the shared login, this caller would need a second
+	// one.
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2024"})
+		waiterErr <- err
+	}()
+
+	release <- struct{}{}
+	if err := <-waiterErr; err != nil {
+		t.Errorf("waiter Import1098() error = %v, want nil", err)
+	}
+
+	if got := logins.Load(); got != 1 {
+		t.Errorf("login calls = %d, want 1", got)
+	}
+}
+
+func Test_tax1099Impl_ReauthorizesOnUnauthorized(t *testing.T) {
+	tests := []struct {
+		name         string
+		acceptToken  string
+		wantLogins   int32
+		wantRequests int32
+		wantErr      error
+	}{
+		{
+			name:         "replays once with the new session",
+			acceptToken:  "session-2",
+			wantLogins:   2,
+			wantRequests: 2,
+		},
+		{
+			name:         "surfaces ErrUnauthorized when the replay is also rejected",
+			acceptToken:  "never",
+			wantLogins:   2,
+			wantRequests: 2,
+			wantErr:      ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logins, requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v1/login" {
+					n := logins.Add(1)
+					json.NewEncoder(w).Encode(loginResponse{SessionID: fmt.Sprintf("session-%d", n)})
+					return
+				}
+
+				requests.Add(1)
+				if r.Header.Get("Authorization") != "Bearer "+tt.acceptToken {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"totalCount":1}`))
+			}))
+			defer server.Close()
+
+			client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", 5*time.Second,
+				WithBaseURL(UrlMain, server.URL+"/api/v1"),
+				WithBaseURL(Url1098, server.URL+"/api/v1"),
+				WithClock(filingSeason),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			res, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2023"})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Import1098() error = %v, want nil", err)
+				}
+				if res.TotalCount != 1 {
+					t.Errorf("TotalCount = %d, want 1", res.TotalCount)
+				}
+			} else if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import1098() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := logins.Load(); got != tt.wantLogins {
+				t.Errorf("login calls = %d, want %d", got, tt.wantLogins)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("import calls = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func Test_tax1099Impl_LoginPathFromCatalog(t *testing.T) {
+	tests := []struct {
+		name      string
+		loginPath string
+		setup     func(t *testing.T, serverURL string) (Environment, []Option)
+	}{
+		{
+			name:      "environment maps the login elsewhere",
+			loginPath: "/api/v1/auth/session",
+			setup: func(t *testing.T, serverURL string) (Environment, []Option) {
+				const env Environment = "login-path-test"
+				err := RegisterEnvironment(env, EnvironmentConfig{
+					Inherits: EnvironmentStaging,
+					Hosts: map[UrlType]string{
+						UrlMain: serverURL + "/api/v1",
+						Url1098: serverURL + "/api/v1",
+					},
+					Endpoints: map[string]Endpoint{
+						"tax1099.authorize": {UrlMain, "auth/session"},
+					},
+				})
+				if err != nil {
+					t.Fatalf("RegisterEnvironment() error = %v", err)
+				}
+				return env, nil
+			},
+		},
+		{
+			name:      "host path contains /login",
+			loginPath: "/login-gw/api/v1/login",
+			setup: func(t *testing.T, serverURL string) (Environment, []Option) {
+				return EnvironmentStaging, []Option{
+					WithBaseURL(UrlMain, serverURL+"/login-gw/api/v1"),
+					WithBaseURL(Url1098, serverURL+"/login-gw/api/v1"),
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logins, requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == tt.loginPath {
+					n := logins.Add(1)
+					json.NewEncoder(w).Encode(loginResponse{SessionID: fmt.Sprintf("session-%d", n)})
+					return
+				}
+
+				// Reject the first session so the request is replayed after a new login.
+				requests.Add(1)
+				if r.Header.Get("Authorization") != "Bearer session-2" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Write([]byte(`{"totalCount":1}`))
+			}))
+			defer server.Close()
+
+			env, opts := tt.setup(t, server.URL)
+			opts = append(opts, WithClock(filingSeason))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			client, err := New(ctx, env, "user", "pass", "key", 5*time.Second, opts...)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			if _, err := client.Import1098(ctx, Submit1098Request{TaxYear: "2023"}); err != nil {
+				t.Fatalf("Import1098() error = %v", err)
+			}
+
+			if got := logins.Load(); got != 2 {
+				t.Errorf("login calls = %d, want 2", got)
+			}
+			if got := requests.Load(); got != 2 {
+				t.Errorf("import calls = %d, want 2", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidMoney is returned when text cannot be parsed as an amount of money.
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// decimalPattern matches an unsigned decimal number, as written in JSON.
+var decimalPattern = regexp.MustCompile(`^(\d+\.?\d*|\.\d+)([eE][+-]?\d{1,3})?$`)
+
+// Money is an amount in U.S. dollars held as a whole number of cents, so sums of
+// ledger amounts are exact. It marshals to a JSON number with two decimals, e.g.
+// 1234.56, and the zero value is $0.00. Money is a struct so a bare number
+// cannot be mistaken for an amount; build one with MoneyFromCents or ParseMoney.
+type Money struct {
+	cents int64
+}
+
+// MoneyFromCents returns the amount of cents as Money.
+func MoneyFromCents(cents int64) Money {
+	return Money{cents: cents}
+}
+
+// MoneyFromFloat rounds f to the nearest cent, halves away from zero. It rounds
+// the shortest decimal that reads back as f, so 1.005 becomes 1.01 even though
+// the nearest float64 is slightly below it. It returns an error wrapping
+// ErrInvalidMoney for NaN, infinities and amounts too large for Money.
+//
+// MoneyFromFloat eases migrating callers that still compute amounts as
+// float64; new code should prefer ParseMoney or MoneyFromCents.
+func MoneyFromFloat(f float64) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidMoney, f)
+	}
+
+	cents, _, err := parseCents(strconv.FormatFloat(f, 'f', -1, 64))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return MoneyFromCents(cents), nil
+}
+
+// ParseMoney parses an amount such as "1234.56", "-12.5", "$1,234.56" or
+// "1234". Amounts with fractions of a cent are rejected.
+func ParseMoney(s string) (Money, error) {
+	cents, exact, err := parseCents(s)
+	if err != nil {
+		return Money{}, err
+	}
+
+	if !exact {
+		return Money{}, fmt.Errorf("%w: %q has fractions of a cent", ErrInvalidMoney, s)
+	}
+
+	return MoneyFromCents(cents), nil
+}
+
+// parseCents parses s as a decimal amount of dollars and returns it in cents,
+// rounded half away from zero, and whether no rounding was needed.
+func parseCents(s string) (int64, bool, error) {
+	text := strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(text, "-")
+	text = strings.TrimPrefix(text, "$")
+	text = strings.ReplaceAll(text, ",", "")
+
+	if !decimalPattern.MatchString(text) {
+		return 0, false, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return 0, false, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+
+	r.Mul(r, big.NewRat(100, 1))
+
+	// Round half away from zero: truncate r + 1/2.
+	num := new(big.Int).Mul(r.Num(), big.NewInt(2))
+	num.Add(num, r.Denom())
+	cents := num.Quo(num, new(big.Int).Mul(r.Denom(), big.NewInt(2)))
+
+	if !cents.IsInt64() {
+		return 0, false, fmt.Errorf("%w: %q is out of range", ErrInvalidMoney, s)
+	}
+
+	v := cents.Int64()
+	if negative {
+		v = -v
+	}
+
+	return v, r.IsInt(), nil
+}
+
+// Cents returns m as a whole number of cents.
+func (m Money) Cents() int64 {
+	return m.cents
+}
+
+// Float64 returns m in dollars. The result may not be exact.
+func (m Money) Float64() float64 {
+	return float64(m.cents) / 100
+}
+
+// Add returns m + n.
+func (m Money) Add(n Money) Money {
+	return Money{cents: m.cents + n.cents}
+}
+
+// Sub returns m - n.
+func (m Money) Sub(n Money) Money {
+	return Money{cents: m.cents - n.cents}
+}
+
+// Mul returns m multiplied by n, e.g. a monthly amount times 12.
+func (m Money) Mul(n int64) Money {
+	return Money{cents: m.cents * n}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{cents: -m.cents}
+}
+
+// IsZero reports whether m is $0.00.
+func (m Money) IsZero() bool {
+	return m.cents == 0
+}
+
+// IsNegative reports whether m is less than $0.00.
+func (m Money) IsNegative() bool {
+	return m.cents < 0
+}
+
+// SumMoney returns the total of amounts.
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, m := range amounts {
+		total.cents += m.cents
+	}
+
+	return total
+}
+
+// String formats m with two decimals and no currency symbol, e.g. "-12.05".
+func (m Money) String() string {
+	sign := ""
+	cents := uint64(m.cents)
+	if m.cents < 0 {
+		// Negate as unsigned so the smallest Money does not overflow.
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON writes m as a JSON number with two decimals.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or string. Amounts with fractions of a cent
+// are rounded to the nearest cent, halves away from zero.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var text string
+	if strings.HasPrefix(string(data), `"`) {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	} else {
+		text = string(data)
+	}
+
+	cents, _, err := parseCents(text)
+	if err != nil {
+		return err
+	}
+
+	*m = MoneyFromCents(cents)
+	return nil
+}
@@ -0,0 +1,162 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_ParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1234.56", want: 123456},
+		{in: "1234.5", want: 123450},
+		{in: "1234", want: 123400},
+		{in: "$1,234.56", want: 123456},
+		{in: "-12.05", want: -1205},
+		{in: "-$0.10", want: -10},
+		{in: " 0.00 ", want: 0},
+		{in: ".5", want: 50},
+		{in: "12.345", wantErr: true},
+		{in: "12.3.4", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "0x10", wantErr: true},
+		{in: "1/2", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidMoney) {
+				t.Errorf("ParseMoney(%q) error = %v, want %v", tt.in, err, ErrInvalidMoney)
+			}
+			continue
+		}
+
+		if err != nil || got.Cents() != tt.want {
+			t.Errorf("ParseMoney(%q) = %s, %v, want %d cents", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func Test_Money_String(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{123456, "1234.56"},
+		{-1205, "-12.05"},
+		{-5, "-0.05"},
+		{math.MaxInt64, "92233720368547758.07"},
+		{math.MinInt64, "-92233720368547758.08"},
+	}
+	for _, tt := range tests {
+		if got := MoneyFromCents(tt.in).String(); got != tt.want {
+			t.Errorf("MoneyFromCents(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_Money_Arithmetic(t *testing.T) {
+	// Ten payments of $0.10 summed as float64 give 0.9999999999999999.
+	var total Money
+	for i := 0; i < 10; i++ {
+		total = total.Add(MoneyFromCents(10))
+	}
+	if total != MoneyFromCents(100) {
+		t.Errorf("sum = %s, want 1.00", total)
+	}
+
+	if got := MoneyFromCents(1000).Sub(MoneyFromCents(1234)); got != MoneyFromCents(-234) || !got.IsNegative() {
+		t.Errorf("Sub() = %s, want -2.34", got)
+	}
+
+	if got := MoneyFromCents(12345).Mul(12); got != MoneyFromCents(148140) {
+		t.Errorf("Mul() = %s, want 1481.40", got)
+	}
+
+	if got := SumMoney(MoneyFromCents(1), MoneyFromCents(2), MoneyFromCents(3)).Neg(); got != MoneyFromCents(-6) {
+		t.Errorf("SumMoney().Neg() = %s, want -0.06", got)
+	}
+}
+
+func Test_MoneyFromFloat(t *testing.T) {
+	tests := []struct {
+		in      float64
+		want    int64
+		wantErr bool
+	}{
+		{in: 1234.56, want: 123456},
+		{in: 1234.5600000001, want: 123456},
+		{in: 1.005, want: 101},
+		{in: 0.285, want: 29},
+		{in: -0.005, want: -1},
+		{in: 1e-9, want: 0},
+		{in: 1e15, want: 100000000000000000},
+		{in: 1e300, wantErr: true},
+		{in: math.NaN(), wantErr: true},
+		{in: math.Inf(1), wantErr: true},
+		{in: math.Inf(-1), wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := MoneyFromFloat(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidMoney) {
+				t.Errorf("MoneyFromFloat(%v) error = %v, want %v", tt.in, err, ErrInvalidMoney)
+			}
+			continue
+		}
+
+		if err != nil || got.Cents() != tt.want {
+			t.Errorf("MoneyFromFloat(%v) = %s, %v, want %d cents", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func Test_Money_JSON(t *testing.T) {
+	form := Form1098{MortgageInterest: MoneyFromCents(123456), MortgagePrincipal: MoneyFromCents(10000000)}
+
+	data, err := json.Marshal(form)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var raw map[string]json.RawMessage
+	json.Unmarshal(data, &raw)
+	if got := string(raw["mortgageInterest"]); got != "1234.56" {
+		t.Errorf("mortgageInterest = %s, want 1234.56", got)
+	}
+	if got := string(raw["mortgagePrincipal"]); got != "100000.00" {
+		t.Errorf("mortgagePrincipal = %s, want 100000.00", got)
+	}
+
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{`1234.56`, 123456},
+		{`"1234.56"`, 123456},
+		{`1234.565`, 123457},
+		{`-1234.565`, -123457},
+		{`1e3`, 100000},
+		{`null`, 0},
+	}
+	for _, tt := range tests {
+		var got Money
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil || got.Cents() != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, %v, want %d cents", tt.in, got, err, tt.want)
+		}
+	}
+
+	var got Money
+	if err := json.Unmarshal([]byte(`"twelve"`), &got); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("Unmarshal(\"twelve\") error = %v, want %v", err, ErrInvalidMoney)
+	}
+}
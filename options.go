@@ -0,0 +1,62 @@
+package tax1099
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures the client returned by New.
+type Option func(*tax1099Impl)
+
+// WithHTTPClient replaces the default http.Client. The supplied client is used
+// as-is, so the timeout passed to New is not applied to it.
+func WithHTTPClient(client *http.Client) Option {
+	return func(t *tax1099Impl) {
+		if client != nil {
+			t.client = client
+		}
+	}
+}
+
+// WithBaseURL overrides the host used for a single UrlType, e.g. to point the
+// client at a local stand-in. The value should include the API version path,
+// such as "http://localhost:8080/api/v1".
+func WithBaseURL(urlType UrlType, baseURL string) Option {
+	return func(t *tax1099Impl) {
+		if t.baseURLs == nil {
+			t.baseURLs = make(map[UrlType]string)
+		}
+
+		t.baseURLs[urlType] = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithClock replaces time.Now when computing and checking token expiry and the
+// tax years the client accepts; see TaxYearWindow.
+func WithClock(now func() time.Time) Option {
+	return func(t *tax1099Impl) {
+		t.clock = now
+	}
+}
+
+// WithoutEagerAuth stops New from logging in immediately. The client will
+// authorize on its first request instead.
+func WithoutEagerAuth() Option {
+	return func(t *tax1099Impl) {
+		t.lazyAuth = true
+	}
+}
+
+// WithLogger sends the client's logs to logger instead of slog.Default. Every
+// record keeps its component and op attributes. A nil logger discards them.
+func WithLogger(logger *slog.Logger) Option {
+	return func(t *tax1099Impl) {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+
+		t.logger = logger
+	}
+}
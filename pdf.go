@@ -1,11 +1,19 @@
 package tax1099
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net/http"
 )
 
+// pdfMagic is the prefix every PDF document starts with.
+var pdfMagic = []byte("%PDF")
+
 type FormStatus string
 
 const (
@@ -18,8 +26,8 @@ type DownloadFormRequest struct {
 	FormType            string     `json:"formType"`
 	Status              FormStatus `json:"status,omitempty"`
 	ClientPayerID       string     `json:"clientPayerId,omitempty"`
-	PayerTin            string     `json:"payerTin,omitempty"`
-	TaxYear             string     `json:"taxYear,omitempty"`
+	PayerTin            TIN        `json:"payerTin,omitempty"`
+	TaxYear             TaxYear    `json:"taxYear,omitempty"`
 	DisregardedEntity   string     `json:"disregardedEntity,omitempty"`
 	CardReferenceID     string     `json:"cardReferenceId,omitempty"`
 	IsAllCopies         bool       `json:"isAllCopies,omitempty"`
@@ -29,41 +37,203 @@ type DownloadFormRequest struct {
 	UnMaskPDF           bool       `json:"unMaskPDF,omitempty"`
 }
 
-// DownloadFilledForm downloads a filled 1099 form PDF based on the provided criteria.
-// Either FormID or the combination of PayerTin and TaxYear must be provided.
-func (t *tax1099Impl) DownloadFilledForm(ctx context.Context, payload DownloadFormRequest) ([]byte, error) {
-	const op = "tax1099.download_filled_form"
+// ErrDownloadTooLarge is returned when a download exceeds the limit set with
+// WithMaxDownloadSize.
+var ErrDownloadTooLarge = errors.New("download exceeds the maximum size")
 
+// WithMaxDownloadSize limits how many bytes a single PDF download may return.
+// Zero, the default, means no limit.
+func WithMaxDownloadSize(n int64) Option {
+	return func(t *tax1099Impl) {
+		t.maxDownloadSize = n
+	}
+}
+
+func (payload DownloadFormRequest) validate() error {
 	if payload.FormID > 0 {
 		if payload.PayerTin != "" || payload.TaxYear != "" {
-			return nil, fmt.Errorf("formId cannot be combined with payerTin or taxYear")
+			return fmt.Errorf("formId cannot be combined with payerTin or taxYear")
 		}
 	} else if payload.PayerTin == "" || payload.TaxYear == "" {
-		return nil, fmt.Errorf("formId or payerTin with taxYear must be provided")
+		return fmt.Errorf("formId or payerTin with taxYear must be provided")
 	}
 
 	if payload.FormType == "" {
-		return nil, fmt.Errorf("formType is required")
+		return fmt.Errorf("formType is required")
+	}
+
+	if payload.TaxYear != "" && !payload.TaxYear.IsValid() {
+		return fmt.Errorf("taxYear must be a four digit year")
+	}
+
+	if payload.PayerTin != "" && !tinDigitsPattern.MatchString(payload.PayerTin.Digits()) {
+		return fmt.Errorf("payerTin must be 9 digits")
 	}
 
 	if payload.Status != "" && payload.Status != FormStatusNotSubmitted && payload.Status != FormStatusSubmitted {
-		return nil, fmt.Errorf("status must be %q or %q", FormStatusNotSubmitted, FormStatusSubmitted)
+		return fmt.Errorf("status must be %q or %q", FormStatusNotSubmitted, FormStatusSubmitted)
+	}
+
+	return nil
+}
+
+// DownloadFilledForm downloads a filled 1099 form PDF based on the provided criteria.
+// Either FormID or the combination of PayerTin and TaxYear must be provided.
+func (t *tax1099Impl) DownloadFilledForm(ctx context.Context, payload DownloadFormRequest) ([]byte, error) {
+	const op = "tax1099.download_filled_form"
+
+	if err := payload.validate(); err != nil {
+		return nil, err
 	}
 
-	slog.InfoContext(ctx, "Downloading filled form PDF...",
+	t.log().InfoContext(ctx, "Downloading filled form PDF...",
 		slog.String("component", component),
 		slog.String("op", op),
 	)
 
-	data, err := t.postForBytes(ctx, op, t.generateFullUrl(UrlMain, "pdf/forms/getpdfs"), payload)
+	url, err := t.endpointURL(op)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := t.postForBytes(ctx, op, url, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	slog.InfoContext(ctx, "...filled form PDF downloaded",
+	t.log().InfoContext(ctx, "...filled form PDF downloaded",
 		slog.String("component", component),
 		slog.String("op", op),
 	)
 
 	return data, nil
 }
+
+// DownloadFilledFormStream is DownloadFilledForm without buffering: the PDF is
+// read from the returned ReadCloser as it arrives, which keeps memory flat for
+// payer-wide downloads. The caller must close it. The %PDF prefix is checked
+// before returning, and reads fail with ErrDownloadTooLarge once the limit set
+// by WithMaxDownloadSize is passed. The client timeout still applies to the
+// whole transfer.
+func (t *tax1099Impl) DownloadFilledFormStream(ctx context.Context, payload DownloadFormRequest) (io.ReadCloser, error) {
+	const op = "tax1099.download_filled_form"
+
+	if err := payload.validate(); err != nil {
+		return nil, err
+	}
+
+	t.log().InfoContext(ctx, "Streaming filled form PDF...",
+		slog.String("component", component),
+		slog.String("op", op),
+	)
+
+	url, err := t.endpointURL(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.ReadCloser
+	if err := t.do(ctx, op, url, payload, streamDecoder{contentType: "application/pdf", body: &body, maxSize: t.maxDownloadSize}); err != nil {
+		return nil, err
+	}
+
+	return newPDFReader(url, body, t.maxDownloadSize)
+}
+
+// DownloadFilledFormTo streams the PDF described by payload into w and returns
+// the number of bytes written. See DownloadFilledFormStream for the checks
+// applied to the response.
+func (t *tax1099Impl) DownloadFilledFormTo(ctx context.Context, payload DownloadFormRequest, w io.Writer) (int64, error) {
+	const op = "tax1099.download_filled_form"
+
+	body, err := t.DownloadFilledFormStream(ctx, payload)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return n, err
+	}
+
+	t.log().InfoContext(ctx, "...filled form PDF streamed",
+		slog.String("component", component),
+		slog.String("op", op),
+		slog.Int64("bytes", n),
+	)
+
+	return n, nil
+}
+
+// pdfReader wraps a response body, having already confirmed it starts with
+// %PDF, and enforces an optional size limit while it is read.
+type pdfReader struct {
+	r      io.Reader
+	closer io.Closer
+	max    int64
+	read   int64
+}
+
+// newPDFReader checks the first bytes of body for the PDF magic prefix. On
+// failure it closes body and returns the same error the buffered download does.
+func newPDFReader(url string, body io.ReadCloser, max int64) (io.ReadCloser, error) {
+	br := bufio.NewReader(body)
+
+	prefix, err := br.Peek(len(pdfMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		body.Close()
+		return nil, fmt.Errorf("failed to read response body from %s: %w", url, err)
+	}
+
+	if bytes.Equal(prefix, zipMagic) {
+		body.Close()
+		return nil, fmt.Errorf("response from %s: %w", url, ErrArchiveResponse)
+	}
+
+	if !bytes.Equal(prefix, pdfMagic) {
+		head, _ := io.ReadAll(io.LimitReader(br, 201))
+		body.Close()
+		return nil, fmt.Errorf("response from %s is not a PDF, body: %s", url, truncateForError(head))
+	}
+
+	return &pdfReader{r: br, closer: body, max: max}, nil
+}
+
+// Read returns at most the bytes up to the limit; a read that would pass it
+// returns only the bytes within it, with ErrDownloadTooLarge.
+func (p *pdfReader) Read(b []byte) (int, error) {
+	if p.max <= 0 {
+		return p.r.Read(b)
+	}
+
+	// Ask for one byte past what is left, to learn whether the body goes on.
+	remaining := p.max - p.read
+	if int64(len(b)) > remaining+1 {
+		b = b[:remaining+1]
+	}
+
+	n, err := p.r.Read(b)
+	if int64(n) > remaining {
+		p.read += remaining
+		return int(remaining), ErrDownloadTooLarge
+	}
+
+	p.read += int64(n)
+	return n, err
+}
+
+// checkContentLength rejects a response whose declared length already passes
+// maxSize, closing its body before any of it is read.
+func checkContentLength(url string, resp *http.Response, maxSize int64) error {
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		resp.Body.Close()
+		return fmt.Errorf("response from %s is %d bytes: %w", url, resp.ContentLength, ErrDownloadTooLarge)
+	}
+
+	return nil
+}
+
+func (p *pdfReader) Close() error {
+	return p.closer.Close()
+}
@@ -1,8 +1,11 @@
 package tax1099
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -111,16 +114,16 @@ func Test_tax1099Impl_DownloadFilledForm_Validation(t *testing.T) {
 
 func Test_tax1099Impl_DownloadFilledForm_ValidInputs(t *testing.T) {
 	tests := []struct {
-		name              string
-		payload           DownloadFormRequest
-		wantURL           string
-		wantMethod        string
-		wantContentType   string
-		wantAccept        string
-		wantAuthHeader    string
-		validateBody      func(t *testing.T, body []byte)
-		mockResponseBody  []byte
-		mockResponseCode  int
+		name             string
+		payload          DownloadFormRequest
+		wantURL          string
+		wantMethod       string
+		wantContentType  string
+		wantAccept       string
+		wantAuthHeader   string
+		validateBody     func(t *testing.T, body []byte)
+		mockResponseBody []byte
+		mockResponseCode int
 	}{
 		{
 			name: "valid request with FormID only",
@@ -167,8 +170,8 @@ func Test_tax1099Impl_DownloadFilledForm_ValidInputs(t *testing.T) {
 				if err := json.Unmarshal(body, &req); err != nil {
 					t.Fatalf("Failed to unmarshal request body: %v", err)
 				}
-				if req.PayerTin != "12-3456789" {
-					t.Errorf("PayerTin = %q, want %q", req.PayerTin, "12-3456789")
+				if req.PayerTin.Digits() != "123456789" {
+					t.Errorf("PayerTin = %q, want %q", req.PayerTin.Digits(), "123456789")
 				}
 				if req.TaxYear != "2024" {
 					t.Errorf("TaxYear = %q, want %q", req.TaxYear, "2024")
@@ -324,8 +327,8 @@ func Test_tax1099Impl_DownloadFilledForm_ValidInputs(t *testing.T) {
 				if err := json.Unmarshal(body, &req); err != nil {
 					t.Fatalf("Failed to unmarshal request body: %v", err)
 				}
-				if req.PayerTin != "98-7654321" {
-					t.Errorf("PayerTin = %q, want %q", req.PayerTin, "98-7654321")
+				if req.PayerTin.Digits() != "987654321" {
+					t.Errorf("PayerTin = %q, want %q", req.PayerTin.Digits(), "987654321")
 				}
 				if req.TaxYear != "2023" {
 					t.Errorf("TaxYear = %q, want %q", req.TaxYear, "2023")
@@ -412,10 +415,10 @@ func Test_tax1099Impl_DownloadFilledForm_ValidInputs(t *testing.T) {
 
 func Test_DownloadFormRequest_JSONMarshaling(t *testing.T) {
 	tests := []struct {
-		name            string
-		payload         DownloadFormRequest
-		wantContain     []string
-		wantNotContain  []string
+		name           string
+		payload        DownloadFormRequest
+		wantContain    []string
+		wantNotContain []string
 	}{
 		{
 			name: "FormID is omitted when zero",
@@ -425,7 +428,7 @@ func Test_DownloadFormRequest_JSONMarshaling(t *testing.T) {
 				FormType: "1099-MISC",
 			},
 			wantContain: []string{
-				`"payerTin":"12-3456789"`,
+				`"payerTin":"123456789"`,
 				`"taxYear":"2024"`,
 				`"formType":"1099-MISC"`,
 			},
@@ -582,3 +585,141 @@ func Test_tax1099Impl_postForBytes_NonPDFResponse(t *testing.T) {
 		})
 	}
 }
+
+func Test_tax1099Impl_DownloadFilledFormTo(t *testing.T) {
+	pdf := append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("x"), 64*1024)...)
+
+	tests := []struct {
+		name             string
+		mockResponseBody []byte
+		maxSize          int64
+		wantErr          error
+		wantErrMsg       string
+	}{
+		{
+			name:             "PDF is streamed to the writer",
+			mockResponseBody: pdf,
+		},
+		{
+			name:             "PDF within the size limit is streamed",
+			mockResponseBody: pdf,
+			maxSize:          int64(len(pdf)),
+		},
+		{
+			name:             "PDF over the size limit is rejected",
+			mockResponseBody: pdf,
+			maxSize:          1024,
+			wantErr:          ErrDownloadTooLarge,
+		},
+		{
+			name:             "JSON error envelope with 200 status is rejected",
+			mockResponseBody: []byte(`{"statusCode":400,"message":"form not found"}`),
+			wantErrMsg:       "is not a PDF",
+		},
+		{
+			name:             "empty body is rejected",
+			mockResponseBody: []byte(""),
+			wantErrMsg:       "is not a PDF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+				if accept := r.Header.Get("Accept"); accept != "application/pdf" {
+					t.Errorf("Accept header = %q, want %q", accept, "application/pdf")
+				}
+				w.Write(tt.mockResponseBody)
+			})
+
+			client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+				WithBaseURL(UrlMain, server.URL+"/api/v1"),
+				WithMaxDownloadSize(tt.maxSize),
+			)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			var buf bytes.Buffer
+			n, gotErr := client.DownloadFilledFormTo(context.Background(), DownloadFormRequest{PayerTin: "12-3456789", TaxYear: "2024", FormType: "1098"}, &buf)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(gotErr, tt.wantErr) {
+					t.Fatalf("DownloadFilledFormTo() error = %v, want %v", gotErr, tt.wantErr)
+				}
+			case tt.wantErrMsg != "":
+				if gotErr == nil || !strings.Contains(gotErr.Error(), tt.wantErrMsg) {
+					t.Fatalf("DownloadFilledFormTo() error = %v, want it to mention %q", gotErr, tt.wantErrMsg)
+				}
+				if buf.Len() != 0 {
+					t.Errorf("DownloadFilledFormTo() wrote %d bytes, want 0", buf.Len())
+				}
+			default:
+				if gotErr != nil {
+					t.Fatalf("DownloadFilledFormTo() error = %v, want nil", gotErr)
+				}
+				if n != int64(len(tt.mockResponseBody)) || !bytes.Equal(buf.Bytes(), tt.mockResponseBody) {
+					t.Errorf("DownloadFilledFormTo() wrote %d bytes, want %d", n, len(tt.mockResponseBody))
+				}
+			}
+		})
+	}
+}
+
+func Test_pdfReader_StopsAtLimit(t *testing.T) {
+	body := append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("x"), 91)...)
+
+	tests := []struct {
+		name     string
+		max      int64
+		wantRead int
+		wantErr  error
+	}{
+		{name: "body over the limit", max: 50, wantRead: 50, wantErr: ErrDownloadTooLarge},
+		{name: "body exactly at the limit", max: 100, wantRead: 100},
+		{name: "no limit", max: 0, wantRead: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newPDFReader("test", io.NopCloser(bytes.NewReader(body)), tt.max)
+			if err != nil {
+				t.Fatalf("newPDFReader() error = %v", err)
+			}
+
+			got, err := io.ReadAll(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadAll() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got) != tt.wantRead {
+				t.Errorf("ReadAll() read %d bytes, want %d", len(got), tt.wantRead)
+			}
+		})
+	}
+}
+
+func Test_tax1099Impl_DownloadFilledFormTo_DeclaredLengthOverLimit(t *testing.T) {
+	pdf := append([]byte("%PDF-1.4 "), bytes.Repeat([]byte("x"), 4096)...)
+
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(pdf)))
+		w.Write(pdf)
+	})
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithMaxDownloadSize(1024),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	_, err = client.DownloadFilledFormTo(context.Background(), DownloadFormRequest{PayerTin: "12-3456789", TaxYear: "2024", FormType: "1098"}, &buf)
+	if !errors.Is(err, ErrDownloadTooLarge) {
+		t.Fatalf("DownloadFilledFormTo() error = %v, want %v", err, ErrDownloadTooLarge)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("DownloadFilledFormTo() wrote %d bytes, want 0", buf.Len())
+	}
+}
@@ -0,0 +1,859 @@
+package tax1099
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// This file holds a deliberately small PDF reader: enough of the object syntax
+// to walk the page tree of the documents Tax1099 generates, pull text out of
+// their content streams and copy pages into new documents. It does not support
+// encryption or filters other than FlateDecode, with or without the TIFF and
+// PNG predictors used by object and cross-reference streams.
+
+type (
+	pdfName   string
+	pdfString string
+	pdfArray  []any
+	pdfDict   map[pdfName]any
+	// pdfKeyword is a bare keyword such as a content stream operator.
+	pdfKeyword string
+)
+
+type pdfRef struct {
+	num, gen int
+}
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte // raw is the stream data exactly as stored, still encoded
+}
+
+var (
+	errPDFEncrypted = errors.New("encrypted PDFs are not supported")
+	errPDFSyntax    = errors.New("malformed PDF")
+)
+
+func isPDFWhite(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+
+	return false
+}
+
+func isPDFDelim(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+
+	return false
+}
+
+type pdfTokenKind int
+
+const (
+	pdfTokEOF pdfTokenKind = iota
+	pdfTokInt
+	pdfTokReal
+	pdfTokName
+	pdfTokString
+	pdfTokKeyword
+	pdfTokDictStart
+	pdfTokDictEnd
+	pdfTokArrayStart
+	pdfTokArrayEnd
+)
+
+type pdfToken struct {
+	kind pdfTokenKind
+	text string // text holds the name, keyword or decoded string
+	i    int64
+	f    float64
+}
+
+// pdfLexer tokenizes PDF object syntax, which is shared by the file structure
+// and content streams.
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhite(c) {
+			l.pos++
+			continue
+		}
+
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+
+		return
+	}
+}
+
+func (l *pdfLexer) next() (pdfToken, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return pdfToken{kind: pdfTokEOF}, nil
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfToken{kind: pdfTokName, text: l.readName()}, nil
+	case c == '(':
+		l.pos++
+		s, err := l.readLiteralString()
+		return pdfToken{kind: pdfTokString, text: s}, err
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfToken{kind: pdfTokDictStart}, nil
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfToken{kind: pdfTokDictEnd}, nil
+	case c == '<':
+		l.pos++
+		s, err := l.readHexString()
+		return pdfToken{kind: pdfTokString, text: s}, err
+	case c == '[':
+		l.pos++
+		return pdfToken{kind: pdfTokArrayStart}, nil
+	case c == ']':
+		l.pos++
+		return pdfToken{kind: pdfTokArrayEnd}, nil
+	case c == '{' || c == '}' || c == ')' || c == '>':
+		// Only used by PostScript functions; treat them as keywords.
+		l.pos++
+		return pdfToken{kind: pdfTokKeyword, text: string(c)}, nil
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhite(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+
+	word := string(l.data[start:l.pos])
+	if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return pdfToken{kind: pdfTokInt, i: i, f: float64(i)}, nil
+	}
+
+	if isPDFNumber(word) {
+		f, err := strconv.ParseFloat(word, 64)
+		if err == nil {
+			return pdfToken{kind: pdfTokReal, f: f}, nil
+		}
+	}
+
+	return pdfToken{kind: pdfTokKeyword, text: word}, nil
+}
+
+// isPDFNumber reports whether word looks like a PDF real, such as "-.5" or "3.".
+func isPDFNumber(word string) bool {
+	if word == "" {
+		return false
+	}
+
+	digits := 0
+	for i, c := range word {
+		switch {
+		case c >= '0' && c <= '9':
+			digits++
+		case (c == '+' || c == '-') && i == 0:
+		case c == '.':
+		default:
+			return false
+		}
+	}
+
+	return digits > 0
+}
+
+func (l *pdfLexer) readName() string {
+	var buf []byte
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhite(c) || isPDFDelim(c) {
+			break
+		}
+
+		if c == '#' && l.pos+2 < len(l.data) {
+			if b, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				buf = append(buf, byte(b))
+				l.pos += 3
+				continue
+			}
+		}
+
+		buf = append(buf, c)
+		l.pos++
+	}
+
+	return string(buf)
+}
+
+func (l *pdfLexer) readLiteralString() (string, error) {
+	var buf []byte
+	depth := 1
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(buf), nil
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return string(buf), errPDFSyntax
+			}
+
+			e := l.data[l.pos]
+			l.pos++
+
+			switch e {
+			case 'n':
+				buf = append(buf, '\n')
+			case 'r':
+				buf = append(buf, '\r')
+			case 't':
+				buf = append(buf, '\t')
+			case 'b':
+				buf = append(buf, '\b')
+			case 'f':
+				buf = append(buf, '\f')
+			case '\r':
+				// A backslash before an end of line continues the string.
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					buf = append(buf, byte(v))
+				} else {
+					buf = append(buf, e)
+				}
+			}
+			continue
+		}
+
+		buf = append(buf, c)
+	}
+
+	return string(buf), errPDFSyntax
+}
+
+func (l *pdfLexer) readHexString() (string, error) {
+	var buf []byte
+	var hi byte
+	half := false
+
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+
+		if c == '>' {
+			if half {
+				buf = append(buf, hi<<4)
+			}
+			return string(buf), nil
+		}
+
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+
+		if half {
+			buf = append(buf, hi<<4|v)
+		} else {
+			hi = v
+		}
+		half = !half
+	}
+
+	return string(buf), errPDFSyntax
+}
+
+// parseObject reads one object. Keywords other than true, false and null are
+// returned as pdfKeyword so content stream operators can be recognized.
+func (l *pdfLexer) parseObject() (any, error) {
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.parseFrom(tok)
+}
+
+func (l *pdfLexer) parseFrom(tok pdfToken) (any, error) {
+	switch tok.kind {
+	case pdfTokEOF:
+		return nil, io.ErrUnexpectedEOF
+	case pdfTokInt:
+		// "num gen R" is an indirect reference; anything else leaves the
+		// integer on its own.
+		save := l.pos
+		gen, err := l.next()
+		if err == nil && gen.kind == pdfTokInt {
+			r, err := l.next()
+			if err == nil && r.kind == pdfTokKeyword && r.text == "R" {
+				return pdfRef{num: int(tok.i), gen: int(gen.i)}, nil
+			}
+		}
+		l.pos = save
+
+		return tok.i, nil
+	case pdfTokReal:
+		return tok.f, nil
+	case pdfTokName:
+		return pdfName(tok.text), nil
+	case pdfTokString:
+		return pdfString(tok.text), nil
+	case pdfTokArrayStart:
+		arr := pdfArray{}
+		for {
+			tok, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+
+			if tok.kind == pdfTokArrayEnd {
+				return arr, nil
+			}
+
+			v, err := l.parseFrom(tok)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case pdfTokDictStart:
+		dict := pdfDict{}
+		for {
+			tok, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+
+			if tok.kind == pdfTokDictEnd {
+				return dict, nil
+			}
+
+			if tok.kind != pdfTokName {
+				return nil, fmt.Errorf("%w: dictionary key is not a name", errPDFSyntax)
+			}
+
+			v, err := l.parseObject()
+			if err != nil {
+				return nil, err
+			}
+			dict[pdfName(tok.text)] = v
+		}
+	case pdfTokKeyword:
+		switch tok.text {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+
+		return pdfKeyword(tok.text), nil
+	}
+
+	return nil, fmt.Errorf("%w: unexpected token", errPDFSyntax)
+}
+
+// pdfDocument is a parsed PDF held entirely in memory.
+type pdfDocument struct {
+	objects map[int]any
+	trailer pdfDict
+}
+
+var pdfObjectHeader = regexp.MustCompile(`(\d+)[ \t\r\n\f\x00]+(\d+)[ \t\r\n\f\x00]+obj\b`)
+
+// parsePDF reads every indirect object in data. Rather than trusting the
+// cross-reference table, which is often slightly off in generated files, it
+// scans the body for "n g obj" headers, skipping over stream data, and lets
+// later definitions replace earlier ones as incremental updates do.
+func parsePDF(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(data, pdfMagic) {
+		return nil, fmt.Errorf("%w: missing %%PDF header", errPDFSyntax)
+	}
+
+	doc := &pdfDocument{objects: make(map[int]any)}
+
+	var trailerPos int
+	var objStreams []*pdfStream
+
+	pos := 0
+	for pos < len(data) {
+		loc := pdfObjectHeader.FindSubmatchIndex(data[pos:])
+		if loc == nil {
+			break
+		}
+
+		start := pos + loc[0]
+		if start > 0 && !isPDFWhite(data[start-1]) && !isPDFDelim(data[start-1]) {
+			// Matched the tail of a longer number.
+			pos = start + 1
+			continue
+		}
+
+		num, _ := strconv.Atoi(string(data[pos+loc[2] : pos+loc[3]]))
+		l := &pdfLexer{data: data, pos: pos + loc[1]}
+
+		obj, err := doc.parseIndirect(l)
+		if err != nil {
+			pos = pos + loc[1]
+			continue
+		}
+
+		if s, ok := obj.(*pdfStream); ok {
+			switch s.dict["Type"] {
+			case pdfName("ObjStm"):
+				objStreams = append(objStreams, s)
+			case pdfName("XRef"):
+				// Cross-reference streams carry the trailer entries.
+				if start >= trailerPos {
+					doc.trailer, trailerPos = s.dict, start
+				}
+			}
+		}
+
+		doc.objects[num] = obj
+		pos = l.pos
+	}
+
+	// Classic trailers.
+	for i := 0; ; {
+		idx := bytes.Index(data[i:], []byte("trailer"))
+		if idx < 0 {
+			break
+		}
+
+		at := i + idx
+		l := &pdfLexer{data: data, pos: at + len("trailer")}
+		if d, err := l.parseObject(); err == nil {
+			if dict, ok := d.(pdfDict); ok && at >= trailerPos {
+				doc.trailer, trailerPos = dict, at
+			}
+		}
+		i = at + len("trailer")
+	}
+
+	// Objects inside object streams only fill gaps; anything defined directly
+	// in the file body is newer.
+	for _, s := range objStreams {
+		doc.loadObjectStream(s)
+	}
+
+	if doc.trailer == nil {
+		doc.trailer = pdfDict{}
+	}
+
+	if _, ok := doc.trailer["Encrypt"]; ok {
+		return nil, errPDFEncrypted
+	}
+
+	if _, ok := doc.resolve(doc.trailer["Root"]).(pdfDict); !ok {
+		// Fall back to any catalog when no usable trailer was found.
+		for num, obj := range doc.objects {
+			if d, ok := obj.(pdfDict); ok && d["Type"] == pdfName("Catalog") {
+				doc.trailer["Root"] = pdfRef{num: num}
+				break
+			}
+		}
+	}
+
+	if _, ok := doc.resolve(doc.trailer["Root"]).(pdfDict); !ok {
+		return nil, fmt.Errorf("%w: no document catalog", errPDFSyntax)
+	}
+
+	return doc, nil
+}
+
+// parseIndirect parses the body of "n g obj ... endobj" with l positioned just
+// after the obj keyword.
+func (doc *pdfDocument) parseIndirect(l *pdfLexer) (any, error) {
+	obj, err := l.parseObject()
+	if err != nil {
+		return nil, err
+	}
+
+	save := l.pos
+	tok, err := l.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok.kind != pdfTokKeyword || tok.text != "stream" {
+		l.pos = save
+		return obj, nil
+	}
+
+	dict, ok := obj.(pdfDict)
+	if !ok {
+		return nil, fmt.Errorf("%w: stream without dictionary", errPDFSyntax)
+	}
+
+	// The stream keyword is followed by CRLF or LF before the data.
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+
+	start := l.pos
+	end := -1
+
+	if n, ok := dict["Length"].(int64); ok && n >= 0 && start+int(n) <= len(l.data) {
+		rest := l.data[start+int(n):]
+		trimmed := bytes.TrimLeft(rest, "\r\n \t")
+		if bytes.HasPrefix(trimmed, []byte("endstream")) {
+			end = start + int(n)
+			l.pos = end + (len(rest) - len(trimmed)) + len("endstream")
+		}
+	}
+
+	if end < 0 {
+		// The length is indirect or wrong; fall back to the endstream keyword.
+		idx := bytes.Index(l.data[start:], []byte("endstream"))
+		if idx < 0 {
+			return nil, fmt.Errorf("%w: unterminated stream", errPDFSyntax)
+		}
+
+		end = start + idx
+		l.pos = end + len("endstream")
+		if end > start && l.data[end-1] == '\n' {
+			end--
+		}
+		if end > start && l.data[end-1] == '\r' {
+			end--
+		}
+	}
+
+	return &pdfStream{dict: dict, raw: l.data[start:end]}, nil
+}
+
+func (doc *pdfDocument) loadObjectStream(s *pdfStream) {
+	data, err := decodeStream(s)
+	if err != nil {
+		return
+	}
+
+	n, _ := s.dict["N"].(int64)
+	first, _ := s.dict["First"].(int64)
+	if n <= 0 || first <= 0 || int(first) > len(data) {
+		return
+	}
+
+	header := &pdfLexer{data: data[:first]}
+	for i := int64(0); i < n; i++ {
+		numTok, err1 := header.next()
+		offTok, err2 := header.next()
+		if err1 != nil || err2 != nil || numTok.kind != pdfTokInt || offTok.kind != pdfTokInt {
+			return
+		}
+
+		num := int(numTok.i)
+		if _, ok := doc.objects[num]; ok {
+			continue
+		}
+
+		// The offsets come from the file; skip any that point outside the
+		// decoded stream rather than index out of range.
+		off := offTok.i
+		if off < 0 || off >= int64(len(data))-first {
+			continue
+		}
+
+		l := &pdfLexer{data: data, pos: int(first + off)}
+		if obj, err := l.parseObject(); err == nil {
+			doc.objects[num] = obj
+		}
+	}
+}
+
+// resolve follows indirect references until it reaches a direct object.
+// Missing objects resolve to nil, as the PDF specification requires.
+func (doc *pdfDocument) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+
+		v = doc.objects[ref.num]
+	}
+
+	return nil
+}
+
+func (doc *pdfDocument) resolveDict(v any) pdfDict {
+	switch d := doc.resolve(v).(type) {
+	case pdfDict:
+		return d
+	case *pdfStream:
+		return d.dict
+	}
+
+	return nil
+}
+
+// decodeStream returns the decoded data of a stream. Only FlateDecode, alone or
+// repeated, is supported, with or without a predictor.
+func decodeStream(s *pdfStream) ([]byte, error) {
+	var filters []pdfName
+
+	switch f := s.dict["Filter"].(type) {
+	case nil:
+	case pdfName:
+		filters = append(filters, f)
+	case pdfArray:
+		for _, v := range f {
+			name, ok := v.(pdfName)
+			if !ok {
+				return nil, fmt.Errorf("%w: bad filter", errPDFSyntax)
+			}
+			filters = append(filters, name)
+		}
+	default:
+		return nil, fmt.Errorf("%w: bad filter", errPDFSyntax)
+	}
+
+	data := s.raw
+	for _, f := range filters {
+		if f != "FlateDecode" && f != "Fl" {
+			return nil, fmt.Errorf("unsupported PDF filter %s", f)
+		}
+
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		decoded, err := io.ReadAll(r)
+		r.Close()
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+
+		data = decoded
+	}
+
+	// Object and cross-reference streams written by most generators use a PNG
+	// predictor on top of FlateDecode.
+	if parms, ok := s.dict["DecodeParms"].(pdfDict); ok && len(filters) == 1 {
+		return unpredict(data, parms)
+	}
+
+	return data, nil
+}
+
+// unpredict reverses the TIFF or PNG predictor named in a FlateDecode stream's
+// DecodeParms.
+func unpredict(data []byte, parms pdfDict) ([]byte, error) {
+	param := func(key pdfName, def int64) int64 {
+		if v, ok := parms[key].(int64); ok && v > 0 {
+			return v
+		}
+		return def
+	}
+
+	predictor := param("Predictor", 1)
+	if predictor == 1 {
+		return data, nil
+	}
+
+	colors, bits, columns := param("Colors", 1), param("BitsPerComponent", 8), param("Columns", 1)
+	if colors > 32 || bits > 16 || columns > 1<<20 {
+		return nil, fmt.Errorf("%w: bad predictor parameters", errPDFSyntax)
+	}
+
+	bpp := int((colors*bits + 7) / 8)            // bytes per pixel, at least 1
+	rowLen := int((colors*bits*columns + 7) / 8) // bytes per row, without the PNG tag
+
+	switch {
+	case predictor == 2:
+		if bits != 8 {
+			return nil, fmt.Errorf("unsupported PDF TIFF predictor with %d bits per component", bits)
+		}
+
+		out := append([]byte(nil), data...)
+		for row := 0; row+rowLen <= len(out); row += rowLen {
+			for i := row + bpp; i < row+rowLen; i++ {
+				out[i] += out[i-bpp]
+			}
+		}
+		return out, nil
+	case predictor >= 10 && predictor <= 15:
+		out := make([]byte, 0, len(data)/(rowLen+1)*rowLen)
+		prev := make([]byte, rowLen)
+
+		for pos := 0; pos+rowLen+1 <= len(data); pos += rowLen + 1 {
+			tag, row := data[pos], append([]byte(nil), data[pos+1:pos+1+rowLen]...)
+
+			for i := range row {
+				var left, upLeft byte
+				if i >= bpp {
+					left, upLeft = row[i-bpp], prev[i-bpp]
+				}
+				up := prev[i]
+
+				switch tag {
+				case 0:
+				case 1:
+					row[i] += left
+				case 2:
+					row[i] += up
+				case 3:
+					row[i] += byte((int(left) + int(up)) / 2)
+				case 4:
+					row[i] += paeth(left, up, upLeft)
+				default:
+					return nil, fmt.Errorf("%w: bad PNG predictor tag %d", errPDFSyntax, tag)
+				}
+			}
+
+			out = append(out, row...)
+			prev = row
+		}
+		return out, nil
+	}
+
+	return nil, fmt.Errorf("unsupported PDF predictor %d", predictor)
+}
+
+// paeth is the PNG Paeth predictor function.
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// pages returns the page dictionaries in document order, with the inheritable
+// attributes of their ancestors copied in, along with the object numbers of
+// every page and page tree node.
+func (doc *pdfDocument) pages() (pages []pdfPage, treeNodes map[int]bool, err error) {
+	root := doc.resolveDict(doc.trailer["Root"])
+	treeNodes = make(map[int]bool)
+
+	var walk func(v any, inherited pdfDict, depth int) error
+	walk = func(v any, inherited pdfDict, depth int) error {
+		if depth > 64 {
+			return fmt.Errorf("%w: page tree too deep", errPDFSyntax)
+		}
+
+		ref, isRef := v.(pdfRef)
+		if isRef {
+			if treeNodes[ref.num] {
+				return fmt.Errorf("%w: page tree cycle", errPDFSyntax)
+			}
+			treeNodes[ref.num] = true
+		}
+
+		node := doc.resolveDict(v)
+		if node == nil {
+			return nil
+		}
+
+		kids, hasKids := doc.resolve(node["Kids"]).(pdfArray)
+		if node["Type"] == pdfName("Page") || !hasKids {
+			if !isRef {
+				return fmt.Errorf("%w: page is not an indirect object", errPDFSyntax)
+			}
+
+			page := pdfDict{}
+			for k, v := range inherited {
+				page[k] = v
+			}
+			for k, v := range node {
+				page[k] = v
+			}
+			pages = append(pages, pdfPage{num: ref.num, dict: page})
+			return nil
+		}
+
+		next := pdfDict{}
+		for k, v := range inherited {
+			next[k] = v
+		}
+		for _, key := range []pdfName{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if v, ok := node[key]; ok {
+				next[key] = v
+			}
+		}
+
+		for _, kid := range kids {
+			if err := walk(kid, next, depth+1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := walk(root["Pages"], pdfDict{}, 0); err != nil {
+		return nil, nil, err
+	}
+
+	return pages, treeNodes, nil
+}
+
+// pdfPage is a page dictionary with inherited attributes resolved.
+type pdfPage struct {
+	num  int
+	dict pdfDict
+}
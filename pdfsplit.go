@@ -0,0 +1,415 @@
+package tax1099
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNoFormKeys is returned by SplitFilledForms when no page yields a key.
+var ErrNoFormKeys = errors.New("no page in the PDF matched a form key")
+
+// FormDocument holds the pages of one recipient split out of a combined PDF.
+type FormDocument struct {
+	Key   string //Key is the value returned by the FormKeyFunc for these pages, such as a masked TIN or account number
+	Pages []int  //Pages lists the 1-based page numbers taken from the combined PDF
+	PDF   []byte //PDF is a standalone document holding only those pages
+}
+
+// FormKeyFunc returns the key identifying the recipient a page belongs to, given
+// the text extracted from that page, or "" when the page carries no key.
+type FormKeyFunc func(pageText string) string
+
+var maskedTINPattern = regexp.MustCompile(`(?:[X*]{3}-[X*]{2}-|[X*]{2}-[X*]{3})\d{4}`)
+
+// KeyByMaskedTIN keys pages by the masked recipient TIN Tax1099 prints on each
+// form, such as "XXX-XX-1234". The payer TIN is printed unmasked, so it is not
+// mistaken for the recipient's. Use KeyByAccountNumber instead for downloads
+// made with UnMaskPDF, or when two recipients share the last four digits.
+func KeyByMaskedTIN() FormKeyFunc {
+	return func(pageText string) string {
+		return maskedTINPattern.FindString(pageText)
+	}
+}
+
+// KeyByAccountNumber keys pages by whichever of the given account numbers (the
+// AcctNo sent with each form) appears on them. Longer account numbers are
+// tried first so that "1001" does not claim a page belonging to "10012".
+func KeyByAccountNumber(accounts ...string) FormKeyFunc {
+	sorted := append([]string(nil), accounts...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	return func(pageText string) string {
+		for _, acct := range sorted {
+			if acct != "" && containsToken(pageText, acct) {
+				return acct
+			}
+		}
+
+		return ""
+	}
+}
+
+// containsToken reports whether s contains token not directly preceded or
+// followed by another letter or digit.
+func containsToken(s, token string) bool {
+	for i := 0; ; {
+		idx := strings.Index(s[i:], token)
+		if idx < 0 {
+			return false
+		}
+
+		start, end := i+idx, i+idx+len(token)
+		if (start == 0 || !isAlnum(s[start-1])) && (end == len(s) || !isAlnum(s[end])) {
+			return true
+		}
+
+		i = start + 1
+	}
+}
+
+func isAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// SplitFilledForms splits a combined PDF, such as one returned by
+// DownloadFilledForm for a PayerTin and TaxYear, into one document per
+// recipient. Each page is assigned by key; pages without a key (instructions
+// printed on the back of a copy, for example) stay with the page before them,
+// and pages for the same key are gathered into one document even when they are
+// not adjacent. Documents are returned in the order their keys first appear.
+// Pages before the first keyed page are returned under the empty key.
+//
+// Each document holds only the objects its own pages reference. Form XObjects
+// listed in a shared resource dictionary but never drawn by the page are left
+// out, widget annotations are cut off from the field tree that spans the whole
+// source, and references to other pages are dropped. Anything else a page
+// references is copied as is: a content stream, annotation or field value the
+// source shares between several recipients' pages appears in each of their
+// documents. SplitFilledForms is meant for the combined downloads Tax1099
+// generates, whose pages each draw their own recipient's form; check the output
+// before relying on it for PDFs from elsewhere. Encrypted PDFs and filters
+// other than FlateDecode are not supported.
+func SplitFilledForms(data []byte, key FormKeyFunc) ([]FormDocument, error) {
+	doc, err := parsePDF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, treeNodes, err := doc.pages()
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := newTextExtractor(doc)
+
+	var order []string
+	groups := map[string][]int{}
+	current, keyed := "", false
+
+	for i, page := range pages {
+		if k := key(extractor.pageText(page.dict)); k != "" {
+			current, keyed = k, true
+		}
+
+		if _, ok := groups[current]; !ok {
+			order = append(order, current)
+		}
+		groups[current] = append(groups[current], i)
+	}
+
+	if !keyed {
+		return nil, ErrNoFormKeys
+	}
+
+	documents := make([]FormDocument, 0, len(order))
+	for _, k := range order {
+		indexes := groups[k]
+
+		subset := make([]pdfPage, len(indexes))
+		numbers := make([]int, len(indexes))
+		for i, idx := range indexes {
+			subset[i] = pages[idx]
+			numbers[i] = idx + 1
+		}
+
+		documents = append(documents, FormDocument{Key: k, Pages: numbers, PDF: writePDFPages(doc, subset, treeNodes)})
+	}
+
+	return documents, nil
+}
+
+// pdfWriter copies objects from a source document into a new one, renumbering
+// them as it goes.
+type pdfWriter struct {
+	src       *pdfDocument
+	buf       bytes.Buffer
+	offsets   []int       // offsets holds the byte offset of each object, indexed by new number
+	renumber  map[int]int // renumber maps source object numbers to new ones
+	queue     []int       // queue holds source objects referenced but not yet written
+	pageNums  map[int]bool
+	treeNodes map[int]bool
+}
+
+// writePDFPages builds a standalone document from pages. Objects reachable from
+// the pages are copied; references to pages outside the set, or to the source
+// page tree, are replaced with null so they cannot drag the rest of the
+// document along.
+func writePDFPages(src *pdfDocument, pages []pdfPage, treeNodes map[int]bool) []byte {
+	w := &pdfWriter{
+		src:       src,
+		renumber:  map[int]int{},
+		pageNums:  map[int]bool{},
+		treeNodes: treeNodes,
+	}
+
+	// Object 1 is the catalog, 2 the page tree, then the pages themselves.
+	w.offsets = make([]int, 3+len(pages))
+	kids := make(pdfArray, len(pages))
+	for i, page := range pages {
+		w.pageNums[page.num] = true
+		w.renumber[page.num] = 3 + i
+		kids[i] = pdfNewRef(3 + i)
+	}
+
+	w.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	w.startObject(1)
+	w.writeValue(pdfDict{"Type": pdfName("Catalog"), "Pages": pdfNewRef(2)})
+	w.endObject()
+
+	w.startObject(2)
+	w.writeValue(pdfDict{"Type": pdfName("Pages"), "Kids": kids, "Count": int64(len(pages))})
+	w.endObject()
+
+	for i, page := range pages {
+		dict := pdfDict{}
+		for k, v := range page.dict {
+			switch k {
+			case "Parent", "B", "StructParents", "Thumb":
+				// The page tree, article beads, structure tree and thumbnails
+				// belong to the source document.
+				continue
+			}
+			dict[k] = v
+		}
+		dict["Parent"] = pdfNewRef(2)
+		dict["Resources"] = src.pageResources(page.dict)
+
+		w.startObject(3 + i)
+		w.writeValue(dict)
+		w.endObject()
+	}
+
+	for len(w.queue) > 0 {
+		num := w.queue[0]
+		w.queue = w.queue[1:]
+
+		w.startObject(w.renumber[num])
+		w.writeValue(src.objects[num])
+		w.endObject()
+	}
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets))
+	for _, off := range w.offsets[1:] {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets), xref)
+
+	return w.buf.Bytes()
+}
+
+func (w *pdfWriter) startObject(num int) {
+	w.offsets[num] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n", num)
+}
+
+func (w *pdfWriter) endObject() {
+	w.buf.WriteString("\nendobj\n")
+}
+
+// pdfNewRef is a reference to an object already numbered in the new document.
+type pdfNewRef int
+
+// pageResources returns the page's resources with the XObject entries it never
+// draws removed. A resource dictionary shared by the whole source document can
+// list the form XObjects of every recipient; copying those would leak their
+// data into this page's document.
+func (doc *pdfDocument) pageResources(page pdfDict) any {
+	resources := doc.resolveDict(page["Resources"])
+	xobjects := doc.resolveDict(resources["XObject"])
+	if xobjects == nil {
+		return page["Resources"]
+	}
+
+	used := pdfDict{}
+	for _, name := range doc.drawnXObjects(page) {
+		if v, ok := xobjects[name]; ok {
+			used[name] = v
+		}
+	}
+
+	pruned := pdfDict{}
+	for k, v := range resources {
+		pruned[k] = v
+	}
+	pruned["XObject"] = used
+
+	return pruned
+}
+
+// drawnXObjects returns the names passed to the Do operator in the page's
+// content streams.
+func (doc *pdfDocument) drawnXObjects(page pdfDict) []pdfName {
+	var streams []*pdfStream
+	switch contents := doc.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = append(streams, contents)
+	case pdfArray:
+		for _, part := range contents {
+			if s, ok := doc.resolve(part).(*pdfStream); ok {
+				streams = append(streams, s)
+			}
+		}
+	}
+
+	var names []pdfName
+	for _, s := range streams {
+		data, err := decodeStream(s)
+		if err != nil {
+			continue
+		}
+
+		l := &pdfLexer{data: data}
+		var last pdfToken
+		for {
+			tok, err := l.next()
+			if err != nil || tok.kind == pdfTokEOF {
+				break
+			}
+
+			switch {
+			case tok.kind == pdfTokKeyword && tok.text == "BI":
+				skipInlineImage(l)
+			case tok.kind == pdfTokKeyword && tok.text == "Do" && last.kind == pdfTokName:
+				names = append(names, pdfName(last.text))
+			}
+			last = tok
+		}
+	}
+
+	return names
+}
+
+// ref returns the new reference for a source reference, queueing the object to
+// be written, or nil when the reference must not be followed.
+func (w *pdfWriter) ref(r pdfRef) any {
+	if num, ok := w.renumber[r.num]; ok {
+		return pdfRef{num: num}
+	}
+
+	if w.treeNodes[r.num] && !w.pageNums[r.num] {
+		return nil
+	}
+
+	if _, ok := w.src.objects[r.num]; !ok {
+		return nil
+	}
+
+	num := len(w.offsets)
+	w.offsets = append(w.offsets, 0)
+	w.renumber[r.num] = num
+	w.queue = append(w.queue, r.num)
+
+	return pdfRef{num: num}
+}
+
+// writeValue serializes v, rewriting references. Widget annotations lose their
+// Parent so the source's form field tree, which spans every page, is not
+// copied; their appearance streams still draw the filled values.
+func (w *pdfWriter) writeValue(v any) {
+	switch v := v.(type) {
+	case nil:
+		w.buf.WriteString("null")
+	case bool:
+		w.buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		w.buf.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		w.buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case pdfName:
+		writePDFName(&w.buf, v)
+	case pdfKeyword:
+		w.buf.WriteString(string(v))
+	case pdfString:
+		fmt.Fprintf(&w.buf, "<%x>", string(v))
+	case pdfNewRef:
+		fmt.Fprintf(&w.buf, "%d 0 R", int(v))
+	case pdfRef:
+		if r, ok := w.ref(v).(pdfRef); ok {
+			fmt.Fprintf(&w.buf, "%d 0 R", r.num)
+		} else {
+			w.buf.WriteString("null")
+		}
+	case pdfArray:
+		w.buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.buf.WriteByte(' ')
+			}
+			w.writeValue(item)
+		}
+		w.buf.WriteByte(']')
+	case pdfDict:
+		_, isAnnot := v["Rect"]
+		isAnnot = isAnnot && v["Subtype"] != nil
+
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			if isAnnot && k == "Parent" {
+				continue
+			}
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+
+		w.buf.WriteString("<<")
+		for _, k := range keys {
+			w.buf.WriteByte(' ')
+			writePDFName(&w.buf, pdfName(k))
+			w.buf.WriteByte(' ')
+			w.writeValue(v[pdfName(k)])
+		}
+		w.buf.WriteString(" >>")
+	case *pdfStream:
+		dict := pdfDict{}
+		for k, val := range v.dict {
+			dict[k] = val
+		}
+		dict["Length"] = int64(len(v.raw))
+
+		w.writeValue(dict)
+		w.buf.WriteString("\nstream\n")
+		w.buf.Write(v.raw)
+		w.buf.WriteString("\nendstream")
+	default:
+		w.buf.WriteString("null")
+	}
+}
+
+func writePDFName(buf *bytes.Buffer, name pdfName) {
+	buf.WriteByte('/')
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < '!' || c > '~' || c == '#' || isPDFDelim(c) {
+			fmt.Fprintf(buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
@@ -0,0 +1,407 @@
+package tax1099
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildTestPDF numbers objects from 1 and wraps them in a PDF with a valid
+// cross-reference table. Empty objects are skipped.
+func buildTestPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		if obj == "" {
+			continue
+		}
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		if off == 0 {
+			// Objects left empty live in an object stream.
+			buf.WriteString("0000000000 65535 f \n")
+			continue
+		}
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+func testStream(content string) string {
+	return fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)
+}
+
+func testFlateStream(extra, content string) string {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode %s >>\nstream\n%s\nendstream", buf.Len(), extra, buf.String())
+}
+
+// utf16Hex encodes s as the two byte codes used by the test's Type0 font.
+func utf16Hex(s string) string {
+	var sb strings.Builder
+	for _, r := range s {
+		fmt.Fprintf(&sb, "%04X", r)
+	}
+	return "<" + sb.String() + ">"
+}
+
+// combinedTestPDF has five pages: recipient 1111's form, its instructions page,
+// recipient 2222's form drawn with a composite font, recipient 1111's second
+// copy drawn through a form XObject, and recipient 3333's form held in a widget
+// annotation. The Helvetica font lives in an object stream.
+func combinedTestPDF() []byte {
+	objStm := "8 0 << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"
+	toUnicode := "/CIDInit /ProcSet findresource begin 12 dict begin begincmap\n" +
+		"1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"1 beginbfrange <0000> <00FF> <0000> endbfrange\n" +
+		"endcmap CMapName currentdict /CMap defineresource pop end end"
+
+	return buildTestPDF(
+		// 1: catalog
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		// 2: page tree with inherited resources
+		"<< /Type /Pages /Kids [3 0 R 4 0 R 5 0 R 6 0 R 7 0 R] /Count 5 /MediaBox [0 0 612 792] /Resources << /Font << /F1 8 0 R /F2 9 0 R >> /XObject << /X1 13 0 R >> >> >>",
+		// 3-7: pages
+		"<< /Type /Page /Parent 2 0 R /Contents 10 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 11 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents [12 0 R] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 18 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 19 0 R /Annots [15 0 R] >>",
+		// 8: in the object stream
+		"",
+		// 9: composite font
+		"<< /Type /Font /Subtype /Type0 /BaseFont /Arial /Encoding /Identity-H /ToUnicode 17 0 R >>",
+		// 10-12: content streams
+		testStream("BT /F1 12 Tf 72 700 Td (Form 1098 Payer TIN 12-3456789) Tj 0 -20 Td (Recipient TIN XXX-XX-1111) Tj ET"),
+		testFlateStream("", "BT /F1 12 Tf 72 700 Td [(Instructions) -250 (for) -250 (Recipient)] TJ ET"),
+		testStream("BT /F2 12 Tf 72 700 Td "+utf16Hex("XXX-XX-2222")+" Tj ET"),
+		// 13: form XObject
+		testFlateStream("/Type /XObject /Subtype /Form /BBox [0 0 612 792]", "BT /F1 12 Tf 72 700 Td (Copy C XXX-XX-1111) Tj ET"),
+		// 14: field shared by widgets on several pages
+		"<< /FT /Tx /T (recipientTin) /Kids [15 0 R 16 0 R] >>",
+		// 15: widget on page 7
+		"<< /Type /Annot /Subtype /Widget /Rect [0 0 100 20] /P 7 0 R /Parent 14 0 R /V (XXX-XX-3333) >>",
+		// 16: widget on another page, which must not be copied
+		"<< /Type /Annot /Subtype /Widget /Rect [0 0 100 20] /P 3 0 R /Parent 14 0 R /V (XXX-XX-1111) >>",
+		// 17: ToUnicode CMap
+		testFlateStream("", toUnicode),
+		// 18-19: content streams
+		testStream("q /X1 Do Q"),
+		testStream("BT /F1 12 Tf 72 700 Td (Form 1098) Tj ET"),
+		// 20: object stream holding object 8
+		fmt.Sprintf("<< /Type /ObjStm /N 1 /First 4 /Length %d >>\nstream\n%s\nendstream", len(objStm), objStm),
+	)
+}
+
+func Test_SplitFilledForms(t *testing.T) {
+	docs, err := SplitFilledForms(combinedTestPDF(), KeyByMaskedTIN())
+	if err != nil {
+		t.Fatalf("SplitFilledForms() error = %v", err)
+	}
+
+	want := []struct {
+		key   string
+		pages []int
+	}{
+		{"XXX-XX-1111", []int{1, 2, 4}},
+		{"XXX-XX-2222", []int{3}},
+		{"XXX-XX-3333", []int{5}},
+	}
+	if len(docs) != len(want) {
+		t.Fatalf("SplitFilledForms() returned %d documents, want %d", len(docs), len(want))
+	}
+
+	for i, w := range want {
+		got := docs[i]
+		if got.Key != w.key || !reflect.DeepEqual(got.Pages, w.pages) {
+			t.Errorf("document %d = key %q pages %v, want key %q pages %v", i, got.Key, got.Pages, w.key, w.pages)
+			continue
+		}
+
+		doc, err := parsePDF(got.PDF)
+		if err != nil {
+			t.Fatalf("document %q does not parse: %v", got.Key, err)
+		}
+
+		pages, _, err := doc.pages()
+		if err != nil {
+			t.Fatalf("document %q pages error = %v", got.Key, err)
+		}
+		if len(pages) != len(w.pages) {
+			t.Errorf("document %q has %d pages, want %d", got.Key, len(pages), len(w.pages))
+		}
+
+		extractor := newTextExtractor(doc)
+		var text strings.Builder
+		for _, page := range pages {
+			if page.dict["MediaBox"] == nil {
+				t.Errorf("document %q page is missing its inherited MediaBox", got.Key)
+			}
+			text.WriteString(extractor.pageText(page.dict))
+		}
+
+		for _, other := range want {
+			has := strings.Contains(text.String(), other.key)
+			if other.key == w.key && !has {
+				t.Errorf("document %q text does not contain its own key", got.Key)
+			}
+			if other.key != w.key && has {
+				t.Errorf("document %q text contains %q", got.Key, other.key)
+			}
+		}
+
+		for _, other := range want {
+			if other.key != w.key && pdfContains(doc, other.key) {
+				t.Errorf("document %q carries data for %q", got.Key, other.key)
+			}
+		}
+	}
+}
+
+// pdfContains reports whether any string or decoded stream in doc contains s.
+func pdfContains(doc *pdfDocument, s string) bool {
+	var walk func(v any) bool
+	walk = func(v any) bool {
+		switch v := v.(type) {
+		case pdfString:
+			return strings.Contains(string(v), s)
+		case pdfArray:
+			for _, item := range v {
+				if walk(item) {
+					return true
+				}
+			}
+		case pdfDict:
+			for _, item := range v {
+				if walk(item) {
+					return true
+				}
+			}
+		case *pdfStream:
+			data, _ := decodeStream(v)
+			return bytes.Contains(data, []byte(s)) || walk(v.dict)
+		}
+		return false
+	}
+
+	for _, obj := range doc.objects {
+		if walk(obj) {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_SplitFilledForms_KeyByAccountNumber(t *testing.T) {
+	data := buildTestPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R 4 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 6 0 R >>",
+		testStream("BT 72 700 Td (Account number LN-10012) Tj ET"),
+		testStream("BT 72 700 Td (Account number LN-1001) Tj ET"),
+	)
+
+	docs, err := SplitFilledForms(data, KeyByAccountNumber("LN-1001", "LN-10012"))
+	if err != nil {
+		t.Fatalf("SplitFilledForms() error = %v", err)
+	}
+
+	if len(docs) != 2 || docs[0].Key != "LN-10012" || docs[1].Key != "LN-1001" {
+		t.Errorf("SplitFilledForms() keys = %v, want [LN-10012 LN-1001]", docs)
+	}
+}
+
+func Test_SplitFilledForms_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name:    "not a PDF",
+			data:    []byte(`{"message":"not found"}`),
+			wantErr: errPDFSyntax,
+		},
+		{
+			name: "no keys",
+			data: buildTestPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				testStream("BT 72 700 Td (Nothing to see) Tj ET"),
+			),
+			wantErr: ErrNoFormKeys,
+		},
+		{
+			name: "object stream offset out of range",
+			data: buildTestPDF(
+				"<< /Type /Catalog /Pages 2 0 R >>",
+				"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+				"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+				testStream("BT 72 700 Td (Nothing to see) Tj ET"),
+				"<< /Type /ObjStm /N 2 /First 14 /Length 17 >>\nstream\n9 -50 10 9999 42\nendstream",
+			),
+			wantErr: ErrNoFormKeys,
+		},
+		{
+			name:    "encrypted",
+			data:    []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n%%EOF\n"),
+			wantErr: errPDFEncrypted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := SplitFilledForms(tt.data, KeyByMaskedTIN()); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SplitFilledForms() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_decodeStream_Predictors(t *testing.T) {
+	const columns, bpp = 6, 2
+	rows := [][]byte{
+		{1, 2, 3, 4, 5, 6},
+		{9, 8, 7, 6, 5, 4},
+		{200, 100, 50, 25, 12, 6},
+		{0, 255, 0, 255, 0, 255},
+		{10, 20, 30, 40, 50, 60},
+	}
+
+	var want []byte
+	for _, row := range rows {
+		want = append(want, row...)
+	}
+
+	// Encode each row with a different PNG filter, tag = row index.
+	var png []byte
+	prev := make([]byte, columns)
+	for tag, row := range rows {
+		png = append(png, byte(tag))
+		for i, x := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+
+			switch tag {
+			case 0:
+				png = append(png, x)
+			case 1:
+				png = append(png, x-left)
+			case 2:
+				png = append(png, x-up)
+			case 3:
+				png = append(png, x-byte((int(left)+int(up))/2))
+			case 4:
+				png = append(png, x-paeth(left, up, upLeft))
+			}
+		}
+		prev = row
+	}
+
+	tiff := append([]byte(nil), want...)
+	for r := 0; r < len(rows); r++ {
+		row := tiff[r*columns : (r+1)*columns]
+		for i := len(row) - 1; i >= bpp; i-- {
+			row[i] -= row[i-bpp]
+		}
+	}
+
+	flate := func(data []byte) []byte {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name      string
+		predictor int64
+		data      []byte
+	}{
+		{name: "PNG", predictor: 12, data: png},
+		{name: "TIFF", predictor: 2, data: tiff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &pdfStream{
+				dict: pdfDict{
+					"Filter":      pdfName("FlateDecode"),
+					"DecodeParms": pdfDict{"Predictor": tt.predictor, "Colors": int64(bpp), "Columns": int64(columns / bpp)},
+				},
+				raw: flate(tt.data),
+			}
+
+			got, err := decodeStream(s)
+			if err != nil {
+				t.Fatalf("decodeStream() error = %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("decodeStream() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// Test_SplitFilledForms_Samples splits real combined downloads saved under
+// testdata/tax1099. The samples carry recipient data, so none are checked in;
+// drop a download there to run it locally.
+func Test_SplitFilledForms_Samples(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "tax1099", "*.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Skip("no sample PDFs in testdata/tax1099")
+	}
+
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			docs, err := SplitFilledForms(data, KeyByMaskedTIN())
+			if err != nil {
+				t.Fatalf("SplitFilledForms() error = %v", err)
+			}
+
+			for _, doc := range docs {
+				split, err := parsePDF(doc.PDF)
+				if err != nil {
+					t.Fatalf("%s: output does not parse: %v", doc.Key, err)
+				}
+
+				for _, other := range docs {
+					if other.Key != doc.Key && pdfContains(split, other.Key) {
+						t.Errorf("%s: output contains %s", doc.Key, other.Key)
+					}
+				}
+			}
+		})
+	}
+}
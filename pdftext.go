@@ -0,0 +1,418 @@
+package tax1099
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf16"
+)
+
+// pdfFont decodes the bytes shown with a font into text. Fonts with a ToUnicode
+// CMap are decoded through it; other simple fonts are read as Latin-1, which is
+// right for the digits and letters that identify a form. Composite fonts
+// without a ToUnicode CMap cannot be decoded and yield no text.
+type pdfFont struct {
+	toUnicode map[string]string
+	codeLens  []int // codeLens lists the code lengths from the CMap's codespace ranges
+	composite bool
+}
+
+func (f *pdfFont) decode(s string) string {
+	if f == nil || f.toUnicode == nil {
+		if f != nil && f.composite {
+			return ""
+		}
+
+		runes := make([]rune, len(s))
+		for i := 0; i < len(s); i++ {
+			runes[i] = rune(s[i])
+		}
+		return string(runes)
+	}
+
+	codeLens := f.codeLens
+	if len(codeLens) == 0 {
+		codeLens = []int{1}
+		if f.composite {
+			codeLens = []int{2}
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, n := range codeLens {
+			if i+n > len(s) {
+				continue
+			}
+
+			if u, ok := f.toUnicode[s[i:i+n]]; ok {
+				sb.WriteString(u)
+				i += n
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			i += codeLens[0]
+		}
+	}
+
+	return sb.String()
+}
+
+// parseToUnicode reads the bfchar and bfrange sections of a ToUnicode CMap.
+func parseToUnicode(data []byte) *pdfFont {
+	font := &pdfFont{toUnicode: make(map[string]string)}
+	lens := map[int]bool{}
+
+	l := &pdfLexer{data: data}
+	var operands []any
+
+	for {
+		tok, err := l.next()
+		if err != nil || tok.kind == pdfTokEOF {
+			break
+		}
+
+		if tok.kind != pdfTokKeyword {
+			v, err := l.parseFrom(tok)
+			if err != nil {
+				break
+			}
+			operands = append(operands, v)
+			continue
+		}
+
+		switch tok.text {
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				if lo, ok := operands[i].(pdfString); ok && len(lo) > 0 {
+					lens[len(lo)] = true
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					font.toUnicode[string(src)] = utf16BEToString(string(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) || len(lo) == 0 || len(lo) > 4 {
+					continue
+				}
+
+				start, end := bytesToInt(string(lo)), bytesToInt(string(hi))
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []rune(utf16BEToString(string(dst)))
+					if len(base) == 0 {
+						continue
+					}
+					for c := start; c <= end; c++ {
+						r := append([]rune(nil), base...)
+						r[len(r)-1] += rune(c - start)
+						font.toUnicode[intToBytes(c, len(lo))] = string(r)
+					}
+				case pdfArray:
+					for j, v := range dst {
+						if s, ok := v.(pdfString); ok && start+j <= end {
+							font.toUnicode[intToBytes(start+j, len(lo))] = utf16BEToString(string(s))
+						}
+					}
+				}
+			}
+		}
+
+		if strings.HasPrefix(tok.text, "end") || strings.HasPrefix(tok.text, "begin") {
+			operands = operands[:0]
+		}
+	}
+
+	for n := 4; n >= 1; n-- {
+		if lens[n] {
+			font.codeLens = append(font.codeLens, n)
+		}
+	}
+
+	return font
+}
+
+func utf16BEToString(s string) string {
+	if len(s)%2 != 0 {
+		return s
+	}
+
+	units := make([]uint16, len(s)/2)
+	for i := range units {
+		units[i] = uint16(s[2*i])<<8 | uint16(s[2*i+1])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+func bytesToInt(s string) int {
+	v := 0
+	for i := 0; i < len(s); i++ {
+		v = v<<8 | int(s[i])
+	}
+	return v
+}
+
+func intToBytes(v, n int) string {
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+	return string(b)
+}
+
+// textExtractor collects the text shown on a page, including text drawn by form
+// XObjects and the values and appearances of form field annotations.
+type textExtractor struct {
+	doc   *pdfDocument
+	fonts map[pdfRef]*pdfFont
+	seen  map[int]bool // seen guards against XObjects that draw themselves
+	sb    strings.Builder
+}
+
+func newTextExtractor(doc *pdfDocument) *textExtractor {
+	return &textExtractor{doc: doc, fonts: map[pdfRef]*pdfFont{}, seen: map[int]bool{}}
+}
+
+// pageText returns the text on page, one line per positioned run of text.
+func (x *textExtractor) pageText(page pdfDict) string {
+	doc := x.doc
+	x.sb.Reset()
+	resources := doc.resolveDict(page["Resources"])
+
+	switch contents := doc.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		x.extractStream(contents, resources, 0)
+	case pdfArray:
+		// Content arrays are concatenated before being interpreted.
+		var buf bytes.Buffer
+		for _, part := range contents {
+			if s, ok := doc.resolve(part).(*pdfStream); ok {
+				if data, err := decodeStream(s); err == nil {
+					buf.Write(data)
+					buf.WriteByte('\n')
+				}
+			}
+		}
+		x.extract(buf.Bytes(), resources, 0)
+	}
+
+	annots, _ := doc.resolve(page["Annots"]).(pdfArray)
+	for _, a := range annots {
+		annot := doc.resolveDict(a)
+		if annot == nil {
+			continue
+		}
+
+		if v, ok := doc.resolve(annot["V"]).(pdfString); ok {
+			x.sb.WriteString(decodeTextString(string(v)))
+			x.sb.WriteByte('\n')
+		}
+
+		if ap := doc.resolveDict(annot["AP"]); ap != nil {
+			if n, ok := doc.resolve(ap["N"]).(*pdfStream); ok {
+				x.extractStream(n, doc.resolveDict(n.dict["Resources"]), 1)
+			}
+		}
+	}
+
+	return x.sb.String()
+}
+
+// decodeTextString decodes a PDF text string, which is either UTF-16BE with a
+// byte order mark or PDFDocEncoding (read here as Latin-1).
+func decodeTextString(s string) string {
+	if strings.HasPrefix(s, "\xfe\xff") {
+		return utf16BEToString(s[2:])
+	}
+
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+func (x *textExtractor) extractStream(s *pdfStream, resources pdfDict, depth int) {
+	data, err := decodeStream(s)
+	if err != nil {
+		return
+	}
+
+	x.extract(data, resources, depth)
+}
+
+func (x *textExtractor) font(resources pdfDict, name pdfName) *pdfFont {
+	fonts := x.doc.resolveDict(resources["Font"])
+	ref := fonts[name]
+	if ref == nil {
+		return nil
+	}
+
+	// Only fonts shared through indirect references are cached; a direct font
+	// dictionary belongs to one resource dictionary and its name may mean a
+	// different font elsewhere.
+	key, isRef := ref.(pdfRef)
+	if f, ok := x.fonts[key]; ok && isRef {
+		return f
+	}
+
+	dict := x.doc.resolveDict(ref)
+	font := &pdfFont{}
+	if s, ok := x.doc.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := decodeStream(s); err == nil {
+			font = parseToUnicode(data)
+		}
+	}
+	font.composite = dict["Subtype"] == pdfName("Type0")
+
+	if isRef {
+		x.fonts[key] = font
+	}
+	return font
+}
+
+func (x *textExtractor) extract(content []byte, resources pdfDict, depth int) {
+	if depth > 8 {
+		return
+	}
+
+	l := &pdfLexer{data: content}
+	var operands []any
+	var font *pdfFont
+
+	for {
+		tok, err := l.next()
+		if err != nil || tok.kind == pdfTokEOF {
+			return
+		}
+
+		if tok.kind != pdfTokKeyword {
+			v, err := l.parseFrom(tok)
+			if err != nil {
+				return
+			}
+			operands = append(operands, v)
+			continue
+		}
+
+		switch tok.text {
+		case "BI":
+			skipInlineImage(l)
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = x.font(resources, name)
+				}
+			}
+		case "Tj", "'", "\"":
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					x.sb.WriteString(font.decode(string(s)))
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				arr, _ := operands[len(operands)-1].(pdfArray)
+				for _, v := range arr {
+					switch v := v.(type) {
+					case pdfString:
+						x.sb.WriteString(font.decode(string(v)))
+					case int64, float64:
+						// A large negative adjustment is how generators
+						// typically draw a word space.
+						if toFloat(v) < -200 {
+							x.sb.WriteByte(' ')
+						}
+					}
+				}
+			}
+		case "Td", "TD", "Tm", "T*", "ET":
+			x.sb.WriteByte('\n')
+		case "Do":
+			if len(operands) > 0 {
+				if name, ok := operands[0].(pdfName); ok {
+					x.extractXObject(resources, name, depth)
+				}
+			}
+		}
+
+		operands = operands[:0]
+	}
+}
+
+func (x *textExtractor) extractXObject(resources pdfDict, name pdfName, depth int) {
+	xobjects := x.doc.resolveDict(resources["XObject"])
+
+	ref, isRef := xobjects[name].(pdfRef)
+	if isRef {
+		if x.seen[ref.num] {
+			return
+		}
+		x.seen[ref.num] = true
+		defer delete(x.seen, ref.num)
+	}
+
+	s, ok := x.doc.resolve(xobjects[name]).(*pdfStream)
+	if !ok || s.dict["Subtype"] != pdfName("Form") {
+		return
+	}
+
+	formResources := x.doc.resolveDict(s.dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+
+	x.extractStream(s, formResources, depth+1)
+}
+
+// skipInlineImage moves l past the binary data of an inline image, which runs
+// from the ID operator to an EI surrounded by whitespace.
+func skipInlineImage(l *pdfLexer) {
+	for {
+		tok, err := l.next()
+		if err != nil || tok.kind == pdfTokEOF {
+			return
+		}
+
+		if tok.kind == pdfTokKeyword && tok.text == "ID" {
+			break
+		}
+	}
+
+	for i := l.pos; i+1 < len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && i > 0 && isPDFWhite(l.data[i-1]) && (i+2 == len(l.data) || isPDFWhite(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+
+	l.pos = len(l.data)
+}
+
+func toFloat(v any) float64 {
+	switch v := v.(type) {
+	case int64:
+		return float64(v)
+	case float64:
+		return v
+	}
+
+	return 0
+}
@@ -0,0 +1,200 @@
+package tax1099
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+?1[-. ]?)?(?:\(\d{3}\)\s?|\b\d{3}[-. ]?)\d{3}[-. ]?\d{4}\b`)
+	tinPattern   = regexp.MustCompile(`\b(?:\d{3}[- ]?\d{2}[- ]?\d{4}|\d{2}[- ]\d{7})\b`)
+)
+
+// maskTail returns s with all but its last four characters replaced by '*'.
+func maskTail(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// Redact replaces email addresses and phone numbers in s with placeholders and
+// masks TIN-shaped numbers, such as "123-45-6789", "123 45 6789" or
+// "123456789", to their last four digits. The client also applies it to the
+// response text it puts in error messages.
+func Redact(s string) string {
+	s = emailPattern.ReplaceAllString(s, "[email]")
+	s = phonePattern.ReplaceAllString(s, "[phone]")
+	return tinPattern.ReplaceAllStringFunc(s, func(tin string) string {
+		return TIN(tin).String()
+	})
+}
+
+// redactingHandler is the slog.Handler returned by NewRedactingHandler.
+type redactingHandler struct {
+	next slog.Handler
+}
+
+// NewRedactingHandler returns a handler that passes records to next with the
+// message and every attribute run through Redact. Values that are neither
+// strings nor numbers, such as errors and structs, are formatted as text first,
+// after resolving any slog.LogValuer, so nothing reaches next unscrubbed:
+//
+//	logger := slog.New(tax1099.NewRedactingHandler(slog.NewJSONHandler(os.Stderr, nil)))
+func NewRedactingHandler(next slog.Handler) slog.Handler {
+	return redactingHandler{next: next}
+}
+
+func (h redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h redactingHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, Redact(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(a))
+		return true
+	})
+
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+
+	return redactingHandler{next: h.next.WithAttrs(redacted)}
+}
+
+func (h redactingHandler) WithGroup(name string) slog.Handler {
+	return redactingHandler{next: h.next.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	return slog.Attr{Key: a.Key, Value: redactValue(a.Value)}
+}
+
+func redactValue(v slog.Value) slog.Value {
+	v = v.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.StringValue(Redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, a := range group {
+			attrs[i] = redactAttr(a)
+		}
+		return slog.GroupValue(attrs...)
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.StringValue(Redact(err.Error()))
+		}
+		return slog.StringValue(Redact(fmt.Sprintf("%+v", v.Any())))
+	default:
+		return v
+	}
+}
+
+// LogValue logs the payer's IDs, TIN type, masked TIN and state, leaving out the
+// name, address and contact details.
+func (p PayerInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("payerId", p.ID),
+		slog.String("clientPayerId", p.ClientID),
+		slog.String("tinType", string(p.TinType)),
+		slog.String("payerTin", p.TaxIdentifer.String()),
+		slog.String("state", p.State),
+	)
+}
+
+// LogValue logs the recipient's IDs, TIN type, masked TIN and state, leaving out
+// the name, address and contact details.
+func (r RecipientInfo) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("recipientId", r.RecipientID),
+		slog.String("clientRecipientId", r.ClientID),
+		slog.String("tinType", string(r.TinType)),
+		slog.String("recipientTin", r.TaxIdentifer.String()),
+		slog.String("state", r.State),
+	)
+}
+
+// formLogValue is the redacted view of a form shared by every form type's
+// LogValue: its name, tax year, masked account number and recipient.
+func formLogValue(f Form, acctNo string) slog.Value {
+	return slog.GroupValue(
+		slog.String("form", f.FormName()),
+		slog.String("taxYear", string(f.FormTaxYear())),
+		slog.String("acctNo", maskTail(acctNo)),
+		slog.Any("recipientInfo", f.Recipient()),
+	)
+}
+
+// LogValue logs the number of payers and forms in the request rather than their
+// contents.
+func (r SubmitRequest[F]) LogValue() slog.Value {
+	return requestLogValue(r.TaxYear, r.Items)
+}
+
+// LogValue logs the request as SubmitRequest.LogValue does.
+func (r PaymentRequest[F]) LogValue() slog.Value {
+	return requestLogValue(r.TaxYear, r.Items)
+}
+
+func requestLogValue[F Form](taxYear TaxYear, items []Item[F]) slog.Value {
+	forms := 0
+	for _, item := range items {
+		forms += len(item.Forms)
+	}
+
+	return slog.GroupValue(
+		slog.String("taxYear", string(taxYear)),
+		slog.Int("items", len(items)),
+		slog.Int("forms", forms),
+	)
+}
+
+// LogValue logs the response's counts, status and validation errors, with the
+// server's messages run through Redact since they may echo submitted values.
+func (r FormResponse) LogValue() slog.Value {
+	errs := make([]slog.Attr, len(r.ValidationErrors))
+	for i, e := range r.ValidationErrors {
+		errs[i] = slog.Group(fmt.Sprint(i),
+			slog.String("field", e.Field),
+			slog.String("source", e.Source),
+			slog.String("message", Redact(e.Message)),
+		)
+	}
+
+	return slog.GroupValue(
+		slog.Int("totalCount", r.TotalCount),
+		slog.Int("results", len(r.Result)),
+		slog.Int("statusCode", r.StatusCode),
+		slog.Bool("isError", r.IsError),
+		slog.String("message", Redact(r.Message)),
+		slog.Attr{Key: "validationErrors", Value: slog.GroupValue(errs...)},
+	)
+}
+
+// LogValue logs the response's counts, status and reference IDs, with the
+// server's messages run through Redact.
+func (r PaymentResponse) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("traceIdentifier", r.TraceIdentifier),
+		slog.Int("totalCount", r.TotalCount),
+		slog.Int("statusCode", r.StatusCode),
+		slog.Bool("isError", r.IsError),
+		slog.Any("referenceIds", r.ReferenceIDs),
+		slog.String("message", Redact(r.Message)),
+		slog.String("paymentResponseMessage", Redact(r.PaymentResponseMessage)),
+	)
+}
@@ -0,0 +1,162 @@
+package tax1099
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Redact(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "tin 123-45-6789 rejected", want: "tin *****6789 rejected"},
+		{in: "tin 123456789 rejected", want: "tin *****6789 rejected"},
+		{in: "ein 12-3456789", want: "ein *****6789"},
+		{in: "tin 123 45 6789 rejected", want: "tin *****6789 rejected"},
+		{in: "ein 12 3456789", want: "ein *****6789"},
+		{in: "mail jane.doe+1099@example.com now", want: "mail [email] now"},
+		{in: "call (555) 123-4567 or 555.123.4567 or +1 555 123 4567", want: "call [phone] or [phone] or [phone]"},
+		{in: "form 12345 for 2024-01-31", want: "form 12345 for 2024-01-31"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_RedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewJSONHandler(&buf, nil))).
+		With("payer", "ein 12-3456789").
+		WithGroup("req")
+
+	logger.Info("failed for 123-45-6789",
+		slog.String("email", "jane@example.com"),
+		slog.Group("contact", slog.String("phone", "555-123-4567")),
+		slog.Any("error", errors.New("recipientTin 987654321 is invalid")),
+		slog.Any("body", struct{ Tin string }{"987-65-4321"}),
+		slog.Int("formId", 123456789),
+	)
+
+	got := buf.String()
+	for _, leak := range []string{"12-3456789", "123-45-6789", "jane@example.com", "555-123-4567", "987654321", "987-65-4321"} {
+		if strings.Contains(got, leak) {
+			t.Errorf("log contains %q: %s", leak, got)
+		}
+	}
+
+	for _, want := range []string{`"msg":"failed for *****6789"`, `"payer":"ein *****6789"`, `"email":"[email]"`, `"phone":"[phone]"`, `"formId":123456789`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("log = %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func Test_LogValue_Redacted(t *testing.T) {
+	payer := PayerInfo{ID: 7, TinType: TinTypeBusiness, TaxIdentifer: "12-3456789", LastNameOrBusinessName: "Lender LLC", Address: "1 Main St", State: "TX", Email: "ops@lender.example", PhoneNumber: "555-123-4567"}
+	recipient := RecipientInfo{TinType: TinTypeIndividual, TaxIdentifer: "123456789", FirstName: "Jane", LastNameOrBusinessName: "Borrower", Address: "2 Elm St", State: "TX", Email: "jane@example.com"}
+	form := Form1098{RecipientInfo: recipient, TaxYear: "2024", AcctNo: "LN-00012345", PropertyAddress: "3 Oak St"}
+	request := Submit1098Request{TaxYear: "2024", Items: []Item1098{{PayerInfo: payer, Forms: []Form1098{form, form}}}}
+	response := FormResponse{TotalCount: 2, ValidationErrors: []ValidationError{{Field: "recipientTin", Source: "forms[0]", Message: "123-45-6789 is invalid"}}}
+
+	for _, handler := range []string{"text", "json"} {
+		var buf bytes.Buffer
+		var h slog.Handler = slog.NewTextHandler(&buf, nil)
+		if handler == "json" {
+			h = slog.NewJSONHandler(&buf, nil)
+		}
+
+		slog.New(h).Info("filing", "payer", payer, "form", form, "request", request, "response", response)
+
+		got := buf.String()
+		for _, leak := range []string{"123456789", "3456789", "Lender LLC", "Jane", "Borrower", "Main St", "Elm St", "Oak St", "example", "555-123-4567", "00012345", "123-45-6789"} {
+			if strings.Contains(got, leak) {
+				t.Errorf("%s log contains %q: %s", handler, leak, got)
+			}
+		}
+
+		for _, want := range []string{"*****6789", "*******2345", "1098", "2024"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s log = %s, want it to contain %s", handler, got, want)
+			}
+		}
+	}
+}
+
+func Test_NonOKResponseBodyNotLogged(t *testing.T) {
+	var buf bytes.Buffer
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"recipientTin 987654321 is invalid"}`))
+	})
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithBaseURL(Url1098, server.URL+"/api/v1"),
+		WithClock(filingSeason),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = client.Validate1098(context.Background(), Submit1098Request{TaxYear: "2024", Items: []Item1098{{Forms: []Form1098{{TaxYear: "2024"}}}}})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Validate1098() error = %v, want *APIError", err)
+	}
+
+	if strings.Contains(buf.String(), "987654321") {
+		t.Errorf("log contains the response body: %s", buf.String())
+	}
+}
+
+func Test_ErrorsRedacted(t *testing.T) {
+	const body = `{"message":"TIN 123 45 6789 for jane@example.com is invalid"}`
+
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"), WithLogger(nil))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	download := DownloadFormRequest{FormID: 1, FormType: "1098"}
+	_, notPDF := client.DownloadFilledForm(context.Background(), download)
+	_, notPDFOrZIP := client.DownloadFilledForms(context.Background(), download)
+
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "APIError message", err: newAPIError("tax1099.import_1098", server.URL, http.StatusBadRequest, []byte(body))},
+		{name: "APIError body", err: newAPIError("tax1099.import_1098", server.URL, http.StatusBadGateway, []byte("TIN 123-45-6789 for jane@example.com"))},
+		{name: "not a PDF", err: notPDF},
+		{name: "not a PDF or ZIP", err: notPDFOrZIP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				t.Fatal("error = nil, want one")
+			}
+
+			msg := tt.err.Error()
+			if strings.Contains(msg, "123 45") || strings.Contains(msg, "123-45") || strings.Contains(msg, "jane@") {
+				t.Errorf("Error() = %q, want the TIN and email redacted", msg)
+			}
+			if !strings.Contains(msg, "*****6789") {
+				t.Errorf("Error() = %q, want the masked TIN", msg)
+			}
+		})
+	}
+}
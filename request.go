@@ -0,0 +1,341 @@
+package tax1099
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// responseDecoder turns a 200 response into the caller's result. decode owns
+// resp.Body and is responsible for closing it.
+type responseDecoder interface {
+	accept() string
+	decode(op, url string, resp *http.Response) error
+}
+
+// jsonDecoder unmarshals the response into v, or discards it when v is nil.
+type jsonDecoder struct {
+	v any
+}
+
+func (jsonDecoder) accept() string { return "application/json" }
+
+func (d jsonDecoder) decode(op, url string, resp *http.Response) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body from %s: %w", url, err)
+	}
+
+	if d.v == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, d.v)
+}
+
+// pdfDecoder buffers the response and checks that it is a PDF no larger than
+// maxSize, when maxSize is set.
+type pdfDecoder struct {
+	data    *[]byte
+	maxSize int64
+}
+
+func (pdfDecoder) accept() string { return "application/pdf" }
+
+func (d pdfDecoder) decode(op, url string, resp *http.Response) error {
+	if err := checkContentLength(url, resp, d.maxSize); err != nil {
+		return err
+	}
+
+	// The provider can return a 200 with a JSON error envelope; without this check
+	// those bytes would be passed along as if they were the requested PDF.
+	body, err := newPDFReader(url, resp.Body, d.maxSize)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body from %s: %w", url, err)
+	}
+
+	*d.data = data
+	return nil
+}
+
+// streamDecoder hands the unread response body to the caller, who must close it.
+// A response declaring more than maxSize bytes, when maxSize is set, is
+// rejected before it is handed over.
+type streamDecoder struct {
+	contentType string
+	body        *io.ReadCloser
+	maxSize     int64
+}
+
+func (d streamDecoder) accept() string { return d.contentType }
+
+func (d streamDecoder) decode(op, url string, resp *http.Response) error {
+	if err := checkContentLength(url, resp, d.maxSize); err != nil {
+		return err
+	}
+
+	*d.body = resp.Body
+	return nil
+}
+
+// do is the single request path shared by every endpoint: it re-authorizes when
+// needed, marshals payload, sends it through the hook chain with retries, and
+// hands a successful response to dec. Non-200 responses become *APIError.
+func (t *tax1099Impl) do(ctx context.Context, op, url string, payload any, dec responseDecoder) error {
+	// Re-authorize if the token has expired, unless this is the login itself
+	if op != authorizeOp {
+		if err := t.ensureToken(ctx); err != nil {
+			return fmt.Errorf("failed to re-authorize: %w", err)
+		}
+	}
+
+	t.log().InfoContext(ctx, "Tax1099 POST",
+		slog.String("component", component),
+		slog.String("op", op),
+		slog.String("url", url),
+	)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.log().ErrorContext(ctx, "Failed to marshal payload",
+			slog.String("component", component),
+			slog.String("op", op),
+			slog.String("payload_type", fmt.Sprintf("%T", payload)),
+			slog.Any("error", err),
+		)
+		return err
+	}
+
+	// Payload bodies for tax forms include TINs, recipient names, addresses,
+	// and dollar amounts. Keep the verbatim body at debug-only so production
+	// INFO output stays free of PII; consumers that need the raw payload can
+	// turn the package's slog level up to debug for a single call.
+	t.log().DebugContext(ctx, "Payload",
+		slog.String("component", component),
+		slog.String("op", op),
+		slog.String("body", string(body)),
+	)
+
+	return t.send(ctx, op, url, body, dec)
+}
+
+// readErrorBody records the first error, other than io.EOF, returned while
+// reading a response body, so a failed decode can be told apart from a
+// connection that broke partway through the body.
+type readErrorBody struct {
+	io.ReadCloser
+	err error
+}
+
+func (b *readErrorBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && b.err == nil {
+		b.err = err
+	}
+
+	return n, err
+}
+
+// send POSTs body to url and hands the 200 response to dec. A 401 on anything
+// but the login endpoint means Tax1099 dropped the session early, so send logs
+// in again and replays the request once.
+func (t *tax1099Impl) send(ctx context.Context, op, url string, body []byte, dec responseDecoder) error {
+	token := t.currentToken()
+
+	err := t.sendWithRetry(ctx, op, url, body, dec, token)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || op == authorizeOp {
+		return err
+	}
+
+	t.log().WarnContext(ctx, "Session rejected, re-authorizing",
+		slog.String("component", component),
+		slog.String("op", op),
+	)
+
+	t.expireToken(token)
+	if err := t.ensureToken(ctx); err != nil {
+		return fmt.Errorf("failed to re-authorize: %w", err)
+	}
+
+	return t.sendWithRetry(ctx, op, url, body, dec, t.currentToken())
+}
+
+// sendWithRetry POSTs body to url with the given token and hands the 200
+// response to dec. Transient failures, including a connection that breaks while
+// dec reads the body, are retried according to the client's retry policy when
+// op is safe to repeat; see RetryPolicy. Decoders that hand the body to the
+// caller unread are not covered once they return.
+func (t *tax1099Impl) sendWithRetry(ctx context.Context, op, url string, body []byte, dec responseDecoder, token string) error {
+	policy := t.retryPolicy
+	retryable := policy.allows(op)
+
+	for attempt := 1; ; attempt++ {
+		canRetry := retryable && attempt < policy.MaxAttempts
+
+		req, err := t.newRequest(ctx, op, url, body, dec.accept(), token)
+		if err != nil {
+			t.log().ErrorContext(ctx, "Failed to create request",
+				slog.String("component", component),
+				slog.String("op", op),
+				slog.Any("error", err),
+			)
+			return err
+		}
+
+		resp, err := t.roundTrip(ctx, op, req, attempt)
+		if err != nil {
+			t.log().ErrorContext(ctx, "Failed to make request",
+				slog.String("component", component),
+				slog.String("op", op),
+				slog.Int("attempt", attempt),
+				slog.Any("error", err),
+			)
+
+			if canRetry && ctx.Err() == nil {
+				if err := policy.wait(ctx, attempt, 0); err != nil {
+					return err
+				}
+				continue
+			}
+
+			return err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			tracked := &readErrorBody{ReadCloser: resp.Body}
+			resp.Body = tracked
+
+			err := dec.decode(op, url, resp)
+			if err == nil {
+				return nil
+			}
+
+			t.log().ErrorContext(ctx, "Failed to decode response",
+				slog.String("component", component),
+				slog.String("op", op),
+				slog.String("url", url),
+				slog.Int("attempt", attempt),
+				slog.Any("error", err),
+			)
+
+			if tracked.err != nil && canRetry && ctx.Err() == nil {
+				if err := policy.wait(ctx, attempt, 0); err != nil {
+					return err
+				}
+				continue
+			}
+
+			return err
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.log().ErrorContext(ctx, "Failed to read response body",
+				slog.String("component", component),
+				slog.String("op", op),
+				slog.Int("attempt", attempt),
+				slog.Any("error", err),
+			)
+
+			if canRetry && ctx.Err() == nil {
+				if err := policy.wait(ctx, attempt, 0); err != nil {
+					return err
+				}
+				continue
+			}
+
+			return err
+		}
+
+		if canRetry && isRetryableStatus(resp.StatusCode) {
+			retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), t.now())
+			if !ok || policy.honorsRetryAfter(retryAfter) {
+				t.log().WarnContext(ctx, "Retrying tax1099 request",
+					slog.String("component", component),
+					slog.String("op", op),
+					slog.Int("attempt", attempt),
+					slog.Int("status_code", resp.StatusCode),
+				)
+
+				if err := policy.wait(ctx, attempt, retryAfter); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		// The body can echo submitted TINs and addresses, so it is left to the
+		// returned *APIError rather than logged.
+		t.log().ErrorContext(ctx, "tax1099 request returned non-200",
+			slog.String("component", component),
+			slog.String("op", op),
+			slog.String("url", url),
+			slog.Int("status_code", resp.StatusCode),
+			slog.Int("body_bytes", len(data)),
+		)
+
+		return newAPIError(op, url, resp.StatusCode, data)
+	}
+}
+
+// newRequest builds the POST for a single attempt and runs the BeforeRequest
+// hooks on it.
+func (t *tax1099Impl) newRequest(ctx context.Context, op, url string, body []byte, accept, token string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) != 0 {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	req.Header.Add("Accept", accept)
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	for _, hook := range t.beforeRequest {
+		if err := hook(ctx, op, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+// roundTrip performs a single HTTP attempt and runs the AfterResponse hooks on
+// its outcome.
+func (t *tax1099Impl) roundTrip(ctx context.Context, op string, req *http.Request, attempt int) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.client.Do(req)
+
+	info := ResponseInfo{
+		Op:       op,
+		Attempt:  attempt,
+		Request:  req,
+		Response: resp,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+	for _, hook := range t.afterResponse {
+		hook(ctx, info)
+	}
+
+	return resp, err
+}
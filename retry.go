@@ -0,0 +1,145 @@
+package tax1099
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how the client retries transient failures: connection
+// errors and 408, 429, 502, 503 and 504 responses. Only operations that are safe
+// to repeat (login, validation and PDF downloads) are retried unless
+// RetryNonIdempotent is set.
+type RetryPolicy struct {
+	MaxAttempts        int           //MaxAttempts is the total number of attempts, including the first; 1 or less disables retries
+	InitialBackoff     time.Duration //InitialBackoff is the wait before the second attempt; it doubles for each attempt after that
+	MaxBackoff         time.Duration //MaxBackoff caps the computed wait between attempts; 0 means no cap. A Retry-After longer than a non-zero MaxBackoff is not waited for: the retries stop and the error is returned
+	Jitter             float64       //Jitter randomizes each wait by up to this fraction of it; values outside 0 to 1 are clamped
+	RetryNonIdempotent bool          //RetryNonIdempotent also retries Import1098 and Submit1098s, which may create duplicates if the first attempt reached Tax1099
+}
+
+// DefaultRetryPolicy is the policy used by New unless WithRetryPolicy is supplied.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     10 * time.Second,
+	Jitter:         0.2,
+}
+
+// NoRetries disables retrying entirely.
+var NoRetries = RetryPolicy{MaxAttempts: 1}
+
+// idempotentOps lists the operations that may be repeated without side effects,
+// besides the validate operation of each registered form type.
+var idempotentOps = map[string]bool{
+	"tax1099.authorize":            true,
+	"tax1099.download_filled_form": true,
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(t *tax1099Impl) {
+		t.retryPolicy = policy
+	}
+}
+
+// allows reports whether op may be retried under this policy.
+func (p RetryPolicy) allows(op string) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+
+	return p.RetryNonIdempotent || idempotentOps[op] || isFormValidateOp(op)
+}
+
+// backoff returns the wait before the attempt following the given one. It is
+// never negative.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < math.MaxInt64/2; i++ {
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+		d *= 2
+	}
+
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	if jitter > 0 && d > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * jitter * float64(d))
+	}
+
+	return max(d, 0)
+}
+
+// honorsRetryAfter reports whether the policy waits for a server-requested
+// delay, which it does unless the delay exceeds a non-zero MaxBackoff.
+func (p RetryPolicy) honorsRetryAfter(retryAfter time.Duration) bool {
+	return p.MaxBackoff <= 0 || retryAfter <= p.MaxBackoff
+}
+
+// wait sleeps before the next attempt, preferring the server's Retry-After when
+// it asks for longer than the computed backoff.
+func (p RetryPolicy) wait(ctx context.Context, attempt int, retryAfter time.Duration) error {
+	d := p.backoff(attempt)
+	if retryAfter > d {
+		d = retryAfter
+	}
+
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// isRetryableStatus reports whether a response status is worth retrying.
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an
+// HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
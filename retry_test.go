@@ -0,0 +1,221 @@
+package tax1099
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_tax1099Impl_send_Retries(t *testing.T) {
+	fastPolicy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	tests := []struct {
+		name         string
+		op           string
+		policy       RetryPolicy
+		statuses     []int
+		retryAfter   string
+		wantAttempts int32
+		wantErr      error
+	}{
+		{
+			name:         "idempotent op recovers after a 502",
+			op:           "tax1099.validate_1098",
+			policy:       fastPolicy,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "idempotent op gives up after max attempts",
+			op:           "tax1099.download_filled_form",
+			policy:       fastPolicy,
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			wantAttempts: 3,
+			wantErr:      ErrServer,
+		},
+		{
+			name:         "non-idempotent op is not retried by default",
+			op:           "tax1099.submit_1098s",
+			policy:       fastPolicy,
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      ErrServer,
+		},
+		{
+			name:         "non-idempotent op is retried when opted in",
+			op:           "tax1099.import_1098",
+			policy:       RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, RetryNonIdempotent: true},
+			statuses:     []int{http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 2,
+		},
+		{
+			name:         "client errors are not retried",
+			op:           "tax1099.validate_1098",
+			policy:       fastPolicy,
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantAttempts: 1,
+			wantErr:      ErrValidation,
+		},
+		{
+			name:         "Retry-After beyond MaxBackoff is not waited for",
+			op:           "tax1099.validate_1098",
+			policy:       fastPolicy,
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "120",
+			wantAttempts: 1,
+			wantErr:      ErrRateLimited,
+		},
+		{
+			name:         "Retry-After is waited for when MaxBackoff is 0",
+			op:           "tax1099.validate_1098",
+			policy:       RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter:   "1",
+			wantAttempts: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+				w.Write([]byte(`{}`))
+			})
+
+			ta := &tax1099Impl{
+				env:            EnvironmentStaging,
+				token:          "test-token",
+				tokenExpiresAt: time.Now().Add(1 * time.Hour),
+				client:         server.Client(),
+				retryPolicy:    tt.policy,
+			}
+
+			err := ta.post(context.Background(), tt.op, server.URL+"/api/v1/forms/1098/validate", Submit1098Request{}, nil)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("post() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("post() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func Test_RetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, w := range want {
+		if got := policy.backoff(i + 1); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter = %v, want within [50ms, 150ms]", got)
+		}
+	}
+}
+
+func Test_RetryPolicy_backoff_Limits(t *testing.T) {
+	uncapped := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond}
+	if got := uncapped.backoff(4); got != 800*time.Millisecond {
+		t.Errorf("backoff(4) without MaxBackoff = %v, want 800ms", got)
+	}
+
+	if got := uncapped.backoff(100); got <= 0 {
+		t.Errorf("backoff(100) without MaxBackoff = %v, want a positive wait", got)
+	}
+
+	wild := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, Jitter: 5}
+	for i := 0; i < 100; i++ {
+		if got := wild.backoff(1); got < 0 || got > 200*time.Millisecond {
+			t.Fatalf("backoff(1) with Jitter 5 = %v, want within [0, 200ms]", got)
+		}
+	}
+
+	negative := RetryPolicy{MaxAttempts: 5, InitialBackoff: 100 * time.Millisecond, Jitter: -1}
+	if got := negative.backoff(1); got != 100*time.Millisecond {
+		t.Errorf("backoff(1) with Jitter -1 = %v, want 100ms", got)
+	}
+}
+
+func Test_tax1099Impl_send_RetriesBrokenBody(t *testing.T) {
+	var attempts atomic.Int32
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			// Promise more than is sent, then drop the connection mid-body.
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"totalCount":`))
+			w.(http.Flusher).Flush()
+
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("Hijack() error = %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+
+		w.Write([]byte(`{"totalCount":2}`))
+	})
+
+	ta := &tax1099Impl{
+		env:            EnvironmentStaging,
+		token:          "test-token",
+		tokenExpiresAt: time.Now().Add(1 * time.Hour),
+		client:         server.Client(),
+		retryPolicy:    RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond},
+	}
+
+	var res FormResponse
+	if err := ta.post(context.Background(), "tax1099.validate_1098", server.URL+"/api/v1/forms/1098/validate", Submit1098Request{}, &res); err != nil {
+		t.Fatalf("post() error = %v, want nil", err)
+	}
+
+	if res.TotalCount != 2 {
+		t.Errorf("TotalCount = %d, want 2", res.TotalCount)
+	}
+
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
@@ -0,0 +1,233 @@
+package tax1099
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestServer returns a server that answers the login endpoint with the given
+// session and delegates everything else to handler. The returned counter tracks
+// login calls.
+func newTestServer(t *testing.T, session string, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/login" {
+			logins.Add(1)
+			json.NewEncoder(w).Encode(loginResponse{SessionID: session})
+			return
+		}
+
+		if handler == nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &logins
+}
+
+// filingSeason is a fixed clock for tests that send forms, so the tax years they
+// use stay within DefaultTaxYearWindow.
+func filingSeason() time.Time {
+	return time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)
+}
+
+// formCapture records the last form request a test server received.
+type formCapture struct {
+	Path string
+	Form map[string]any // Form is the first form of the first item
+}
+
+// newFormTestClient returns a client whose hosts all point at a test server
+// that records each form request and answers it with an empty success.
+func newFormTestClient(t *testing.T) (Tax1099, *formCapture) {
+	t.Helper()
+
+	capture := &formCapture{}
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		capture.Path = r.URL.Path
+
+		var body struct {
+			Items []struct {
+				Forms []map[string]any `json:"forms"`
+			} `json:"items"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Items) > 0 && len(body.Items[0].Forms) > 0 {
+			capture.Form = body.Items[0].Forms[0]
+		}
+
+		w.Write([]byte(`{"totalCount":1}`))
+	})
+
+	opts := []Option{WithBaseURL(UrlMain, server.URL+"/api/v1"), WithClock(filingSeason)}
+	opts = append(opts, WithBaseURL(UrlPayment, server.URL+"/api/v1"))
+	for _, spec := range RegisteredForms() {
+		opts = append(opts, WithBaseURL(spec.UrlType, server.URL+"/api/v1"))
+	}
+
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	return client, capture
+}
+
+func Test_New_Options(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       func(serverURL string) []Option
+		wantLogins int32
+		wantErr    string
+	}{
+		{
+			name: "eager auth logs in against overridden base URL",
+			opts: func(serverURL string) []Option {
+				return []Option{WithBaseURL(UrlMain, serverURL+"/api/v1/")}
+			},
+			wantLogins: 1,
+		},
+		{
+			name: "WithoutEagerAuth skips the login",
+			opts: func(serverURL string) []Option {
+				return []Option{WithBaseURL(UrlMain, serverURL+"/api/v1"), WithoutEagerAuth()}
+			},
+			wantLogins: 0,
+		},
+		{
+			name: "WithHTTPClient is used against the default hosts",
+			opts: func(serverURL string) []Option {
+				return []Option{WithHTTPClient(&http.Client{Transport: failingTransport{}}), WithRetryPolicy(NoRetries)}
+			},
+			wantLogins: 0,
+			wantErr:    "no network in tests: tax1099api.1099cloud.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, logins := newTestServer(t, "test-token", nil)
+
+			_, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second, tt.opts(server.URL)...)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("New() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("New() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+
+			if got := logins.Load(); got != tt.wantLogins {
+				t.Errorf("login calls = %d, want %d", got, tt.wantLogins)
+			}
+		})
+	}
+}
+
+func Test_tax1099Impl_generateFullUrl(t *testing.T) {
+	ta := &tax1099Impl{env: EnvironmentProduction}
+	WithBaseURL(UrlPayment, "http://localhost:8080/api/v1/")(ta)
+
+	tests := []struct {
+		urlType UrlType
+		want    string
+	}{
+		{UrlMain, "https://app.tax1099.com/api/v1/login"},
+		{Url1098, "https://form1098.tax1099.com/api/v1/login"},
+		{UrlPayment, "http://localhost:8080/api/v1/login"},
+	}
+	for _, tt := range tests {
+		if got := ta.generateFullUrl(tt.urlType, "login"); got != tt.want {
+			t.Errorf("generateFullUrl(%q) = %q, want %q", tt.urlType, got, tt.want)
+		}
+	}
+}
+
+func Test_WithClock_ReauthorizesAfterExpiry(t *testing.T) {
+	server, logins := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("%PDF-1.4 mock pdf content"))
+	})
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithClock(func() time.Time { return now }),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	payload := DownloadFormRequest{FormID: 1, FormType: "1098"}
+
+	now = now.Add(54 * time.Minute)
+	if _, err := client.DownloadFilledForm(context.Background(), payload); err != nil {
+		t.Fatalf("DownloadFilledForm() error = %v", err)
+	}
+	if got := logins.Load(); got != 1 {
+		t.Errorf("login calls before expiry = %d, want 1", got)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := client.DownloadFilledForm(context.Background(), payload); err != nil {
+		t.Fatalf("DownloadFilledForm() error = %v", err)
+	}
+	if got := logins.Load(); got != 2 {
+		t.Errorf("login calls after expiry = %d, want 2", got)
+	}
+}
+
+// failingTransport fails every request without touching the network.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	return nil, &testTransportError{host: r.URL.Host}
+}
+
+type testTransportError struct{ host string }
+
+func (e *testTransportError) Error() string { return "no network in tests: " + e.host }
+
+func Test_WithLogger(t *testing.T) {
+	var defaultBuf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&defaultBuf, nil)))
+
+	server, _ := newTestServer(t, "test-token", nil)
+
+	var buf bytes.Buffer
+	_, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "Authorizing...") || !strings.Contains(got, "component=go-tax1099 op=tax1099.authorize") {
+		t.Errorf("logger got %q, want the authorize records with component and op", got)
+	}
+
+	_, err = New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+		WithBaseURL(UrlMain, server.URL+"/api/v1"),
+		WithLogger(nil),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if defaultBuf.Len() != 0 {
+		t.Errorf("slog.Default got %q, want nothing", defaultBuf.String())
+	}
+}
@@ -0,0 +1,159 @@
+package tax1099
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidTaxYear is returned for a tax year that is not a four digit year.
+	ErrInvalidTaxYear = errors.New("invalid tax year")
+	// ErrTaxYearNotAccepted is returned for a tax year outside the client's
+	// TaxYearWindow.
+	ErrTaxYearNotAccepted = errors.New("tax year is not accepted")
+	// ErrTaxYearMismatch is returned when a form's tax year differs from the
+	// tax year of the request it is sent in.
+	ErrTaxYearMismatch = errors.New("form tax year does not match the request")
+)
+
+// TaxYear is the four digit year a form is filed for, e.g. "2024". It is a
+// string so it marshals the way Tax1099 expects and untyped constants such as
+// "2024" can still be assigned to it.
+type TaxYear string
+
+// TaxYearOf returns year as a TaxYear.
+func TaxYearOf(year int) TaxYear {
+	return TaxYear(fmt.Sprintf("%04d", year))
+}
+
+// ParseTaxYear parses a four digit year, ignoring surrounding spaces.
+func ParseTaxYear(s string) (TaxYear, error) {
+	y := TaxYear(strings.TrimSpace(s))
+	if !y.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTaxYear, s)
+	}
+
+	return y, nil
+}
+
+// IsValid reports whether y is a four digit year.
+func (y TaxYear) IsValid() bool {
+	if len(y) != 4 {
+		return false
+	}
+
+	for _, c := range y {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Int returns y as a number, or 0 if y is not valid.
+func (y TaxYear) Int() int {
+	if !y.IsValid() {
+		return 0
+	}
+
+	n, _ := strconv.Atoi(string(y))
+	return n
+}
+
+// TaxYearWindow is the range of tax years a client sends forms for. Requests
+// for other years fail with ErrTaxYearNotAccepted before anything is sent.
+//
+// The rule follows the IRS filing calendar rather than a limit published by
+// Tax1099: information returns for a tax year are filed in the following
+// calendar year (recipient copies by January 31, e-filing by March 31), so in
+// 2025 the latest regular filing is for 2024. Late and corrected returns for
+// earlier years are still accepted by the IRS, so by default there is no lower
+// bound. Tax1099 may apply limits of its own, which it reports as validation
+// errors.
+type TaxYearWindow struct {
+	MaxPriorYears    int  //MaxPriorYears, when positive, rejects years more than this many before the last completed calendar year; 0 means no lower bound
+	AllowCurrentYear bool //AllowCurrentYear also accepts the calendar year in progress, e.g. for a final return filed before the year ends
+}
+
+// DefaultTaxYearWindow is the window used by New unless WithTaxYearWindow or
+// WithoutTaxYearCheck is supplied: any year up to the last completed calendar
+// year.
+var DefaultTaxYearWindow = TaxYearWindow{}
+
+// WithTaxYearWindow replaces DefaultTaxYearWindow.
+func WithTaxYearWindow(window TaxYearWindow) Option {
+	return func(t *tax1099Impl) {
+		t.taxYearWindow = window
+		t.skipTaxYearCheck = false
+	}
+}
+
+// WithoutTaxYearCheck sends requests for any well formed tax year and leaves it
+// to Tax1099 to reject years it does not accept.
+func WithoutTaxYearCheck() Option {
+	return func(t *tax1099Impl) {
+		t.skipTaxYearCheck = true
+	}
+}
+
+// Range returns the first and last tax years w accepts at now. first is empty
+// when there is no lower bound.
+func (w TaxYearWindow) Range(now time.Time) (first, last TaxYear) {
+	latest := now.Year() - 1
+	if w.AllowCurrentYear {
+		latest = now.Year()
+	}
+
+	if w.MaxPriorYears > 0 {
+		first = TaxYearOf(now.Year() - 1 - w.MaxPriorYears)
+	}
+
+	return first, TaxYearOf(latest)
+}
+
+// Check returns an error wrapping ErrInvalidTaxYear or ErrTaxYearNotAccepted
+// unless y is within w at now.
+func (w TaxYearWindow) Check(y TaxYear, now time.Time) error {
+	if !y.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidTaxYear, string(y))
+	}
+
+	first, last := w.Range(now)
+	switch {
+	case y > last:
+		return fmt.Errorf("%w: %s has not ended, the latest year accepted is %s", ErrTaxYearNotAccepted, y, last)
+	case first != "" && y < first:
+		return fmt.Errorf("%w: %s is before %s", ErrTaxYearNotAccepted, y, first)
+	}
+
+	return nil
+}
+
+// requestTaxYear returns the tax year of a request: taxYear when it is set and
+// otherwise the year its forms are for. Every form with a year of its own must
+// agree with it.
+func requestTaxYear[F Form](taxYear TaxYear, items []Item[F]) (TaxYear, error) {
+	source := "the request"
+	for i, item := range items {
+		for j, form := range item.Forms {
+			year := form.FormTaxYear()
+			switch {
+			case year == "":
+			case taxYear == "":
+				taxYear, source = year, fmt.Sprintf("items[%d].forms[%d]", i, j)
+			case year != taxYear:
+				return "", fmt.Errorf("items[%d].forms[%d] is for %s, %s for %s: %w", i, j, year, source, taxYear, ErrTaxYearMismatch)
+			}
+		}
+	}
+
+	if !taxYear.IsValid() {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTaxYear, string(taxYear))
+	}
+
+	return taxYear, nil
+}
@@ -0,0 +1,236 @@
+package tax1099
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ParseTaxYear(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    TaxYear
+		wantErr bool
+	}{
+		{in: "2024", want: "2024"},
+		{in: " 2024 ", want: "2024"},
+		{in: "24", wantErr: true},
+		{in: "20245", wantErr: true},
+		{in: "TY24", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTaxYear(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidTaxYear) {
+				t.Errorf("ParseTaxYear(%q) error = %v, want %v", tt.in, err, ErrInvalidTaxYear)
+			}
+			continue
+		}
+
+		if err != nil || got != tt.want || got.Int() != 2024 {
+			t.Errorf("ParseTaxYear(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func Test_TaxYearWindow_Check(t *testing.T) {
+	now := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		window    TaxYearWindow
+		year      TaxYear
+		wantFirst TaxYear
+		wantLast  TaxYear
+		wantErr   error
+	}{
+		{name: "last year", year: "2024", wantLast: "2024"},
+		{name: "late filing for an old year", year: "2015", wantLast: "2024"},
+		{name: "current year before it ends", year: "2025", wantLast: "2024", wantErr: ErrTaxYearNotAccepted},
+		{name: "next year", year: "2026", wantLast: "2024", wantErr: ErrTaxYearNotAccepted},
+		{name: "malformed year", year: "24", wantLast: "2024", wantErr: ErrInvalidTaxYear},
+		{name: "empty year", year: "", wantLast: "2024", wantErr: ErrInvalidTaxYear},
+		{name: "current year allowed", window: TaxYearWindow{AllowCurrentYear: true}, year: "2025", wantLast: "2025"},
+		{name: "within MaxPriorYears", window: TaxYearWindow{MaxPriorYears: 3}, year: "2021", wantFirst: "2021", wantLast: "2024"},
+		{name: "before MaxPriorYears", window: TaxYearWindow{MaxPriorYears: 3}, year: "2020", wantFirst: "2021", wantLast: "2024", wantErr: ErrTaxYearNotAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if first, last := tt.window.Range(now); first != tt.wantFirst || last != tt.wantLast {
+				t.Errorf("Range() = %q, %q, want %q, %q", first, last, tt.wantFirst, tt.wantLast)
+			}
+
+			if err := tt.window.Check(tt.year, now); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Check(%q) error = %v, want %v", tt.year, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_TaxYearCheckedBeforeSending(t *testing.T) {
+	var requests atomic.Int32
+	var gotTaxYear atomic.Value
+	server, _ := newTestServer(t, "test-token", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+
+		var body struct {
+			TaxYear TaxYear `json:"taxYear"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotTaxYear.Store(body.TaxYear)
+
+		w.Write([]byte(`{"totalCount":1}`))
+	})
+
+	newClient := func(opts ...Option) Tax1099 {
+		opts = append([]Option{
+			WithBaseURL(UrlMain, server.URL+"/api/v1"),
+			WithBaseURL(Url1098, server.URL+"/api/v1"),
+			WithBaseURL(UrlPayment, server.URL+"/api/v1"),
+			WithClock(filingSeason),
+		}, opts...)
+
+		client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second, opts...)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+
+		return client
+	}
+	client := newClient()
+
+	items := func(years ...TaxYear) []Item1098 {
+		var forms []Form1098
+		for _, year := range years {
+			forms = append(forms, Form1098{TaxYear: year})
+		}
+
+		return []Item1098{{Forms: forms}}
+	}
+
+	tests := []struct {
+		name        string
+		call        func() error
+		wantErr     error
+		wantTaxYear TaxYear
+	}{
+		{
+			name: "matching years are sent",
+			call: func() error {
+				_, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2024", Items: items("2024", "")})
+				return err
+			},
+			wantTaxYear: "2024",
+		},
+		{
+			name: "form for another year",
+			call: func() error {
+				_, err := client.Validate1098(context.Background(), Submit1098Request{TaxYear: "2024", Items: items("2024", "2023")})
+				return err
+			},
+			wantErr: ErrTaxYearMismatch,
+		},
+		{
+			name: "empty request year is taken from the forms",
+			call: func() error {
+				_, err := client.Validate1098(context.Background(), Submit1098Request{Items: items("", "2023", "2023")})
+				return err
+			},
+			wantTaxYear: "2023",
+		},
+		{
+			name: "empty request year with forms that disagree",
+			call: func() error {
+				_, err := client.Validate1098(context.Background(), Submit1098Request{Items: items("2023", "2022")})
+				return err
+			},
+			wantErr: ErrTaxYearMismatch,
+		},
+		{
+			name: "late correction for an old year",
+			call: func() error {
+				_, err := client.Submit1098s(context.Background(), Submit1098sRequest{TaxYear: "2019", IsCorrected: true})
+				return err
+			},
+			wantTaxYear: "2019",
+		},
+		{
+			name: "current year before the filing season",
+			call: func() error {
+				_, err := client.Submit1098s(context.Background(), Submit1098sRequest{TaxYear: "2025"})
+				return err
+			},
+			wantErr: ErrTaxYearNotAccepted,
+		},
+		{
+			name: "year before a configured window",
+			call: func() error {
+				client := newClient(WithTaxYearWindow(TaxYearWindow{MaxPriorYears: 3}))
+				_, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2019"})
+				return err
+			},
+			wantErr: ErrTaxYearNotAccepted,
+		},
+		{
+			name: "current year with the check turned off",
+			call: func() error {
+				client := newClient(WithoutTaxYearCheck())
+				_, err := client.Import1098(context.Background(), Submit1098Request{TaxYear: "2025"})
+				return err
+			},
+			wantTaxYear: "2025",
+		},
+		{
+			name: "missing year",
+			call: func() error {
+				_, err := client.Import1098(context.Background(), Submit1098Request{Items: items("")})
+				return err
+			},
+			wantErr: ErrInvalidTaxYear,
+		},
+		{
+			name: "missing year with the check turned off",
+			call: func() error {
+				client := newClient(WithoutTaxYearCheck())
+				_, err := client.Import1098(context.Background(), Submit1098Request{})
+				return err
+			},
+			wantErr: ErrInvalidTaxYear,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			gotTaxYear.Store(TaxYear(""))
+
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			wantRequests := int32(0)
+			if tt.wantErr == nil {
+				wantRequests = 1
+			}
+			if got := requests.Load(); got != wantRequests {
+				t.Errorf("requests sent = %d, want %d", got, wantRequests)
+			}
+
+			if got := gotTaxYear.Load(); got != tt.wantTaxYear {
+				t.Errorf("taxYear sent = %q, want %q", got, tt.wantTaxYear)
+			}
+		})
+	}
+}
+
+func Test_DownloadFormRequest_TaxYear(t *testing.T) {
+	_, err := (&tax1099Impl{}).DownloadFilledForm(context.Background(), DownloadFormRequest{PayerTin: "123456789", TaxYear: "24", FormType: "1098"})
+	if err == nil || err.Error() != "taxYear must be a four digit year" {
+		t.Errorf("DownloadFilledForm() error = %v, want a tax year format error", err)
+	}
+}
@@ -0,0 +1,148 @@
+package tax1099
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTIN is returned for a TIN that is not a well formed SSN, ITIN or EIN
+// for its TinType.
+var ErrInvalidTIN = errors.New("invalid TIN")
+
+var (
+	tinDigitsPattern = regexp.MustCompile(`^\d{9}$`)
+	ssnLayoutPattern = regexp.MustCompile(`^\d{3}-\d{2}-\d{4}$`)
+	einLayoutPattern = regexp.MustCompile(`^\d{2}-\d{7}$`)
+)
+
+// invalidEINPrefixes lists the EIN prefixes the IRS has never assigned to a
+// campus.
+var invalidEINPrefixes = map[string]bool{
+	"00": true, "07": true, "08": true, "09": true, "17": true, "18": true, "19": true, "28": true,
+	"29": true, "49": true, "69": true, "70": true, "78": true, "79": true, "89": true, "96": true,
+	"97": true,
+}
+
+// TIN is a taxpayer identification number: an SSN or ITIN for an individual or
+// an EIN for a business. It may be written with or without dashes and spaces,
+// e.g. "123-45-6789" or "12-3456789"; it is sent to Tax1099 as nine digits.
+//
+// TIN masks all but the last four digits when printed or logged, so a payer or
+// recipient can be logged without exposing the number. Use Digits for the full
+// number.
+type TIN string
+
+// ParseTIN normalizes s and checks it is a well formed TIN for tinType.
+func ParseTIN(s string, tinType TinType) (TIN, error) {
+	t := TIN(s)
+	if err := t.Validate(tinType); err != nil {
+		return "", err
+	}
+
+	return t.Normalize(), nil
+}
+
+// Normalize returns t with dashes and spaces removed.
+func (t TIN) Normalize() TIN {
+	return TIN(t.Digits())
+}
+
+// Digits returns the unmasked number with dashes and spaces removed.
+func (t TIN) Digits() string {
+	return strings.NewReplacer("-", "", " ", "").Replace(string(t))
+}
+
+// Validate returns an error wrapping ErrInvalidTIN unless t is a well formed SSN
+// or ITIN for TinTypeIndividual or EIN for TinTypeBusiness. Numbers the IRS
+// never issues, such as SSNs in area 000 or 666 and EINs with an unassigned
+// prefix, are rejected.
+func (t TIN) Validate(tinType TinType) error {
+	if problem := tinProblem(tinType, t); problem != "" {
+		return fmt.Errorf("%w: %s %s", ErrInvalidTIN, t, problem)
+	}
+
+	return nil
+}
+
+// tinProblem describes what is wrong with tin for tinType, or returns "" if it is
+// well formed.
+func tinProblem(tinType TinType, tin TIN) string {
+	raw := strings.TrimSpace(string(tin))
+	digits := tin.Digits()
+
+	switch tinType {
+	case TinTypeIndividual:
+		if !tinDigitsPattern.MatchString(digits) || einLayoutPattern.MatchString(raw) {
+			return "must be a 9 digit SSN or ITIN for an individual"
+		}
+
+		if digits[0] == '9' {
+			return itinProblem(digits)
+		}
+
+		return ssnProblem(digits)
+	case TinTypeBusiness:
+		if !tinDigitsPattern.MatchString(digits) || ssnLayoutPattern.MatchString(raw) {
+			return "must be a 9 digit EIN for a business"
+		}
+
+		if invalidEINPrefixes[digits[:2]] {
+			return fmt.Sprintf("has EIN prefix %s, which the IRS does not assign", digits[:2])
+		}
+
+		return ""
+	default:
+		return fmt.Sprintf("has unknown TIN type %q", tinType)
+	}
+}
+
+func ssnProblem(digits string) string {
+	area := digits[:3]
+	switch {
+	case area == "000" || area == "666":
+		return fmt.Sprintf("has SSN area number %s, which is never issued", area)
+	case digits[3:5] == "00":
+		return "has SSN group number 00, which is never issued"
+	case digits[5:] == "0000":
+		return "has SSN serial number 0000, which is never issued"
+	}
+
+	return ""
+}
+
+// itinProblem checks the group number of a TIN starting with 9, which is an ITIN
+// only in the ranges the IRS assigns: 50-65, 70-88, 90-92 and 94-99.
+func itinProblem(digits string) string {
+	group, _ := strconv.Atoi(digits[3:5])
+	switch {
+	case group >= 50 && group <= 65, group >= 70 && group <= 88, group >= 90 && group <= 92, group >= 94:
+		return ""
+	}
+
+	return fmt.Sprintf("starts with 9 but group number %02d is not an ITIN range", group)
+}
+
+// String returns t with all but the last four digits masked, e.g. "*****6789".
+func (t TIN) String() string {
+	return maskTail(t.Digits())
+}
+
+// GoString masks t as String does, for the %#v verb.
+func (t TIN) GoString() string {
+	return strconv.Quote(t.String())
+}
+
+// LogValue masks t as String does.
+func (t TIN) LogValue() slog.Value {
+	return slog.StringValue(t.String())
+}
+
+// MarshalJSON writes the unmasked number with dashes and spaces removed.
+func (t TIN) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Digits())
+}
@@ -0,0 +1,97 @@
+package tax1099
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_ParseTIN(t *testing.T) {
+	tests := []struct {
+		name    string
+		tin     string
+		tinType TinType
+		want    TIN
+		wantErr bool
+	}{
+		{name: "SSN with dashes", tin: "123-45-6789", tinType: TinTypeIndividual, want: "123456789"},
+		{name: "SSN with spaces", tin: " 123 45 6789 ", tinType: TinTypeIndividual, want: "123456789"},
+		{name: "ITIN", tin: "912-70-1234", tinType: TinTypeIndividual, want: "912701234"},
+		{name: "EIN with dash", tin: "12-3456789", tinType: TinTypeBusiness, want: "123456789"},
+		{name: "EIN without dash", tin: "987654321", tinType: TinTypeBusiness, want: "987654321"},
+		{name: "SSN area 000", tin: "000-12-3456", tinType: TinTypeIndividual, wantErr: true},
+		{name: "SSN area 666", tin: "666-12-3456", tinType: TinTypeIndividual, wantErr: true},
+		{name: "SSN group 00", tin: "123-00-4567", tinType: TinTypeIndividual, wantErr: true},
+		{name: "SSN serial 0000", tin: "123-45-0000", tinType: TinTypeIndividual, wantErr: true},
+		{name: "9xx outside ITIN ranges", tin: "912-34-5678", tinType: TinTypeIndividual, wantErr: true},
+		{name: "9xx in group 93", tin: "912-93-5678", tinType: TinTypeIndividual, wantErr: true},
+		{name: "EIN layout for an individual", tin: "12-3456789", tinType: TinTypeIndividual, wantErr: true},
+		{name: "SSN layout for a business", tin: "123-45-6789", tinType: TinTypeBusiness, wantErr: true},
+		{name: "unassigned EIN prefix", tin: "07-1234567", tinType: TinTypeBusiness, wantErr: true},
+		{name: "too short", tin: "1234567", tinType: TinTypeBusiness, wantErr: true},
+		{name: "letters", tin: "12-345678A", tinType: TinTypeBusiness, wantErr: true},
+		{name: "unknown TIN type", tin: "123456789", tinType: "Trust", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTIN(tt.tin, tt.tinType)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTIN) {
+					t.Fatalf("ParseTIN() error = %v, want %v", err, ErrInvalidTIN)
+				}
+
+				if strings.Contains(err.Error(), TIN(tt.tin).Digits()) && len(TIN(tt.tin).Digits()) > 4 {
+					t.Errorf("ParseTIN() error %q exposes the TIN", err)
+				}
+				return
+			}
+
+			if err != nil || got != tt.want {
+				t.Errorf("ParseTIN() = %q, %v, want %q", string(got), err, string(tt.want))
+			}
+		})
+	}
+}
+
+func Test_TIN_Masking(t *testing.T) {
+	recipient := RecipientInfo{TinType: TinTypeIndividual, TaxIdentifer: "123-45-6789", LastNameOrBusinessName: "Borrower"}
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v"} {
+		if got := fmt.Sprintf(format, recipient); strings.Contains(got, "12345") || !strings.Contains(got, "*****6789") {
+			t.Errorf("Sprintf(%q) = %s, want the TIN masked", format, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("filing", "tin", recipient.TaxIdentifer)
+	if got := buf.String(); !strings.Contains(got, "tin=*****6789") {
+		t.Errorf("log = %s, want tin=*****6789", got)
+	}
+
+	if got := TIN("12").String(); got != "**" {
+		t.Errorf("String() = %q, want %q", got, "**")
+	}
+}
+
+func Test_TIN_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(PayerInfo{TaxIdentifer: "12-3456789"})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	if !bytes.Contains(data, []byte(`"payerTin":"123456789"`)) {
+		t.Errorf("Marshal() = %s, want the TIN as nine digits", data)
+	}
+}
+
+func Test_DownloadFormRequest_PayerTin(t *testing.T) {
+	_, err := (&tax1099Impl{}).DownloadFilledForm(context.Background(), DownloadFormRequest{PayerTin: "12-34567", TaxYear: "2024", FormType: "1098"})
+	if err == nil || err.Error() != "payerTin must be 9 digits" {
+		t.Errorf("DownloadFilledForm() error = %v, want a payerTin format error", err)
+	}
+}
@@ -0,0 +1,174 @@
+package tax1099
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Token is a Tax1099 session together with the time the client stops trusting it.
+type Token struct {
+	SessionID string    `json:"sessionId"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// TokenStore persists sessions so they can be reused by other clients or
+// processes logging in with the same credentials. Authorize loads a token
+// before logging in and saves the new one afterwards.
+//
+// Keys are opaque hashes of the login URL, email and app key; they never contain
+// the credentials themselves.
+type TokenStore interface {
+	// Load returns the token saved under key. ok is false when nothing is saved.
+	Load(ctx context.Context, key string) (token Token, ok bool, err error)
+	// Save stores token under key, replacing any previous token.
+	Save(ctx context.Context, key string, token Token) error
+}
+
+// WithTokenStore makes Authorize reuse unexpired sessions from store and save
+// new ones to it.
+func WithTokenStore(store TokenStore) Option {
+	return func(t *tax1099Impl) {
+		t.tokenStore = store
+	}
+}
+
+// tokenStoreKey derives the store key for a login.
+func tokenStoreKey(loginURL, email, appKey string) string {
+	sum := sha256.Sum256([]byte(loginURL + "\x00" + email + "\x00" + appKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryTokenStore is a TokenStore that shares sessions between clients in the
+// same process.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: make(map[string]Token)}
+}
+
+func (s *MemoryTokenStore) Load(_ context.Context, key string) (Token, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[key]
+	return token, ok, nil
+}
+
+func (s *MemoryTokenStore) Save(_ context.Context, key string, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[key] = token
+	return nil
+}
+
+// FileTokenStore is a TokenStore that keeps each session in its own file,
+// encrypted with AES-GCM, so short-lived processes can share a login.
+type FileTokenStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+// NewFileTokenStore returns a FileTokenStore writing to dir, which is created if
+// needed. key must be 16, 24 or 32 bytes and should come from a secret manager,
+// not from source code.
+func NewFileTokenStore(dir string, key []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token store key: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileTokenStore{dir: dir, aead: aead}, nil
+}
+
+func (s *FileTokenStore) path(key string) string {
+	return filepath.Join(s.dir, key+".token")
+}
+
+func (s *FileTokenStore) Load(_ context.Context, key string) (Token, bool, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Token{}, false, nil
+	}
+	if err != nil {
+		return Token{}, false, err
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(data) < nonceSize {
+		return Token{}, false, fmt.Errorf("token file %s is truncated", s.path(key))
+	}
+
+	// The key is passed as additional data so a file copied under another
+	// key's name fails to decrypt.
+	plain, err := s.aead.Open(nil, data[:nonceSize], data[nonceSize:], []byte(key))
+	if err != nil {
+		return Token{}, false, fmt.Errorf("failed to decrypt token file %s: %w", s.path(key), err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(plain, &token); err != nil {
+		return Token{}, false, err
+	}
+
+	return token, true, nil
+}
+
+func (s *FileTokenStore) Save(_ context.Context, key string, token Token) error {
+	plain, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+
+	data := s.aead.Seal(nonce, nonce, plain, []byte(key))
+
+	// Write to a temporary file and rename it into place so concurrent
+	// processes never read a partially written token.
+	tmp, err := os.CreateTemp(s.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
@@ -0,0 +1,103 @@
+package tax1099
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_FileTokenStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	key := bytes.Repeat([]byte{1}, 32)
+
+	store, err := NewFileTokenStore(dir, key)
+	if err != nil {
+		t.Fatalf("NewFileTokenStore() error = %v", err)
+	}
+
+	if _, ok, err := store.Load(ctx, "missing"); ok || err != nil {
+		t.Fatalf("Load(missing) = ok %v, err %v, want false, nil", ok, err)
+	}
+
+	want := Token{SessionID: "secret-session", ExpiresAt: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)}
+	if err := store.Save(ctx, "abc", want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "abc.token"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if bytes.Contains(data, []byte("secret-session")) {
+		t.Errorf("token file contains the session id in plain text")
+	}
+
+	got, ok, err := store.Load(ctx, "abc")
+	if err != nil || !ok {
+		t.Fatalf("Load() = ok %v, err %v, want true, nil", ok, err)
+	}
+	if got.SessionID != want.SessionID || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+
+	other, err := NewFileTokenStore(dir, bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatalf("NewFileTokenStore() error = %v", err)
+	}
+	if _, _, err := other.Load(ctx, "abc"); err == nil {
+		t.Errorf("Load() with the wrong key succeeded, want decryption error")
+	}
+
+	if _, err := NewFileTokenStore(dir, []byte("short")); err == nil {
+		t.Errorf("NewFileTokenStore() with a 5 byte key succeeded, want error")
+	}
+}
+
+func Test_WithTokenStore_ReusesSessionAcrossClients(t *testing.T) {
+	server, logins := newTestServer(t, "test-token", nil)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryTokenStore()
+	newClient := func() *tax1099Impl {
+		client, err := New(context.Background(), EnvironmentStaging, "user", "pass", "key", time.Second,
+			WithBaseURL(UrlMain, server.URL+"/api/v1"),
+			WithClock(func() time.Time { return now }),
+			WithTokenStore(store),
+		)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		return client.(*tax1099Impl)
+	}
+
+	first := newClient()
+	second := newClient()
+
+	if got := logins.Load(); got != 1 {
+		t.Errorf("login calls = %d, want 1", got)
+	}
+	if second.currentToken() != first.currentToken() {
+		t.Errorf("second client token = %q, want %q", second.currentToken(), first.currentToken())
+	}
+
+	// A stored session past its expiry is not reused.
+	now = now.Add(time.Hour)
+	newClient()
+	if got := logins.Load(); got != 2 {
+		t.Errorf("login calls after expiry = %d, want 2", got)
+	}
+
+	// Nor is one Tax1099 has rejected.
+	third := newClient()
+	third.expireToken(third.currentToken())
+	if err := third.ensureToken(context.Background()); err != nil {
+		t.Fatalf("ensureToken() error = %v", err)
+	}
+	if got := logins.Load(); got != 3 {
+		t.Errorf("login calls after rejection = %d, want 3", got)
+	}
+}
@@ -36,7 +36,7 @@ type PayerInfo struct {
 	ID                     int     `json:"payerId"`                 //PayerID is the unique identifier for the payer in Tax1099's system
 	ClientID               string  `json:"clientPayerId,omitempty"` //ClientPayerID is the unique identifier for the payer in your system
 	TinType                TinType `json:"tinType"`                 //TinType is the type of TIN for the payer, whether business or individual
-	TaxIdentifer           string  `json:"payerTin"`                //TaxIdentifier is the payer's TIN, SSN, or EIN with no dashes
+	TaxIdentifer           TIN     `json:"payerTin"`                //TaxIdentifier is the payer's TIN, SSN, or EIN, sent with no dashes
 	FirstName              string  `json:"firstName,omitempty"`     //FirstName is the first name of the payer, if individual
 	MiddleName             string  `json:"middleName,omitempty"`    //MiddleName is the middle name of the payer, if individual
 	LastNameOrBusinessName string  `json:"lastNameOrBusinessName"`  //LastNameOrBusinessName is the last name of the payer, if individual, or the business name
@@ -60,7 +60,7 @@ type RecipientInfo struct {
 	RecipientID            int     `json:"recipientId,omitempty"`       //RecipientID is the unique identifier for the recipient in Tax1099's system
 	ClientID               string  `json:"clientRecipientId,omitempty"` //ClientRecipientID is the unique identifier for the recipient in your system
 	TinType                TinType `json:"tinType"`                     //TinType is the type of TIN for the recipient, whether business or individual
-	TaxIdentifer           string  `json:"recipientTin"`                //TaxIdentifier is the recipient's TIN, SSN, or EIN with no dashes.
+	TaxIdentifer           TIN     `json:"recipientTin"`                //TaxIdentifier is the recipient's TIN, SSN, or EIN, sent with no dashes
 	FirstName              string  `json:"firstName,omitempty"`         //FirstName is the first name of the recipient, if individual
 	MiddleName             string  `json:"middleName,omitempty"`        //MiddleName is the middle name of the recipient, if individual
 	LastNameOrBusinessName string  `json:"lastNameOrBusinessName"`      //LastNameOrBusinessName is the last name of the recipient, if individual, or the business name
@@ -76,3 +76,12 @@ type RecipientInfo struct {
 	AttentionTo            string  `json:"attentionTo,omitempty"`       //AttentionTo is the name of the person to whom the form should be addressed, if a business
 	IsActive               bool    `json:"isActive"`                    //IsActive is used to indicate if the recipient is active or inactive
 }
+
+// StateWithholding represents one state row of a 1099 form. Forms that report
+// state information allow up to two rows.
+type StateWithholding struct {
+	State            string `json:"state"`            //State is the two-letter abbreviation for the state
+	PayerStateNo     string `json:"payerStateNo"`     //PayerStateNo is the payer's state identification number
+	StateIncome      Money  `json:"stateIncome"`      //StateIncome is the amount of income reported to the state
+	StateTaxWithheld Money  `json:"stateTaxWithheld"` //StateTaxWithheld is the amount of state income tax withheld
+}
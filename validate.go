@@ -0,0 +1,199 @@
+package tax1099
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	zipCodePattern = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+)
+
+// usStates lists the two-letter USPS codes accepted for State, including
+// territories and military post offices.
+var usStates = map[string]bool{
+	"AL": true, "AK": true, "AZ": true, "AR": true, "CA": true, "CO": true, "CT": true, "DE": true,
+	"DC": true, "FL": true, "GA": true, "HI": true, "ID": true, "IL": true, "IN": true, "IA": true,
+	"KS": true, "KY": true, "LA": true, "ME": true, "MD": true, "MA": true, "MI": true, "MN": true,
+	"MS": true, "MO": true, "MT": true, "NE": true, "NV": true, "NH": true, "NJ": true, "NM": true,
+	"NY": true, "NC": true, "ND": true, "OH": true, "OK": true, "OR": true, "PA": true, "RI": true,
+	"SC": true, "SD": true, "TN": true, "TX": true, "UT": true, "VT": true, "VA": true, "WA": true,
+	"WV": true, "WI": true, "WY": true,
+	"AS": true, "GU": true, "MP": true, "PR": true, "VI": true, "FM": true, "MH": true, "PW": true,
+	"AA": true, "AE": true, "AP": true,
+}
+
+// validator collects ValidationErrors for the fields of one object.
+type validator struct {
+	errs []ValidationError
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) nonNegative(field string, amount Money) {
+	if amount.IsNegative() {
+		v.add(field, "must not be negative")
+	}
+}
+
+// tin checks that tin is a well formed SSN or ITIN for individuals and an EIN
+// for businesses, as TIN.Validate does.
+func (v *validator) tin(tinField string, tinType TinType, tin TIN) {
+	hasType := v.required("tinType", string(tinType))
+	if !v.required(tinField, string(tin)) || !hasType {
+		return
+	}
+
+	if tinType != TinTypeIndividual && tinType != TinTypeBusiness {
+		v.add("tinType", "must be %q or %q", TinTypeIndividual, TinTypeBusiness)
+		return
+	}
+
+	if problem := tinProblem(tinType, tin); problem != "" {
+		v.add(tinField, "%s", problem)
+	}
+}
+
+// address checks the state and ZIP code of a U.S. address. Foreign addresses are
+// only checked for presence.
+func (v *validator) address(address, city, state, zipCode, country string) {
+	v.required("address", address)
+	v.required("city", city)
+
+	if country != "" && !strings.EqualFold(country, "US") {
+		return
+	}
+
+	if v.required("state", state) && !usStates[state] {
+		v.add("state", "must be a two-letter U.S. state abbreviation")
+	}
+
+	if v.required("zipCode", zipCode) && !zipCodePattern.MatchString(zipCode) {
+		v.add("zipCode", "must be a 5 digit ZIP code or ZIP+4 with a hyphen")
+	}
+}
+
+func (v *validator) date(field string, d Date) {
+	if !d.IsZero() && !d.IsValid() {
+		v.add(field, "must be a real calendar date")
+	}
+}
+
+// within returns errs with path prepended to each Source.
+func within(path string, errs []ValidationError) []ValidationError {
+	for i := range errs {
+		if errs[i].Source == "" {
+			errs[i].Source = path
+		} else {
+			errs[i].Source = path + "." + errs[i].Source
+		}
+	}
+
+	return errs
+}
+
+// Validate checks the payer's required fields, TIN format and address without
+// calling the API.
+func (p PayerInfo) Validate() []ValidationError {
+	var v validator
+
+	v.tin("payerTin", p.TinType, p.TaxIdentifer)
+	v.required("lastNameOrBusinessName", p.LastNameOrBusinessName)
+	v.address(p.Address, p.City, p.State, p.ZipCode, p.Country)
+	v.required("phone", p.PhoneNumber)
+
+	return v.errs
+}
+
+// Validate checks the recipient's required fields, TIN format and address
+// without calling the API.
+func (r RecipientInfo) Validate() []ValidationError {
+	var v validator
+
+	v.tin("recipientTin", r.TinType, r.TaxIdentifer)
+	v.required("lastNameOrBusinessName", r.LastNameOrBusinessName)
+	v.address(r.Address, r.City, r.State, r.ZipCode, r.Country)
+	v.required("phone", r.PhoneNumber)
+
+	return v.errs
+}
+
+// Validate checks the request without calling the API: the tax year, every
+// payer, every form's agreement with the request's tax year (or, when that is
+// empty, with the first form that has one) and, for form types
+// with a Validate method such as Form1098, the forms themselves. Source holds
+// the path of the object at fault, e.g. "items[0].forms[2].recipientInfo".
+func (r SubmitRequest[F]) Validate() []ValidationError {
+	return validateItems(r.TaxYear, r.Items)
+}
+
+// Validate checks the request as SubmitRequest.Validate does.
+func (r PaymentRequest[F]) Validate() []ValidationError {
+	return validateItems(r.TaxYear, r.Items)
+}
+
+func validateItems[F Form](taxYear TaxYear, items []Item[F]) []ValidationError {
+	var v validator
+
+	if taxYear == "" {
+		taxYear = formsTaxYear(items)
+		if taxYear == "" {
+			v.add("taxYear", "is required when no form has a tax year")
+		}
+	} else if !taxYear.IsValid() {
+		v.add("taxYear", "must be a four digit year")
+	}
+
+	if len(items) == 0 {
+		v.add("items", "at least one item is required")
+	}
+
+	errs := v.errs
+	for i, item := range items {
+		itemPath := fmt.Sprintf("items[%d]", i)
+		errs = append(errs, within(itemPath+".payerInfo", item.PayerInfo.Validate())...)
+
+		if len(item.Forms) == 0 {
+			errs = append(errs, ValidationError{Field: "forms", Source: itemPath, Message: "at least one form is required"})
+		}
+
+		for j, form := range item.Forms {
+			formPath := fmt.Sprintf("%s.forms[%d]", itemPath, j)
+
+			if year := form.FormTaxYear(); year != "" && year != taxYear {
+				errs = append(errs, ValidationError{Field: "taxYear", Source: formPath, Message: fmt.Sprintf("must match the request tax year %s", taxYear)})
+			}
+
+			if f, ok := any(form).(interface{ Validate() []ValidationError }); ok {
+				errs = append(errs, within(formPath, f.Validate())...)
+			}
+		}
+	}
+
+	return errs
+}
+
+// formsTaxYear returns the tax year of the first form that has one.
+func formsTaxYear[F Form](items []Item[F]) TaxYear {
+	for _, item := range items {
+		for _, form := range item.Forms {
+			if year := form.FormTaxYear(); year != "" {
+				return year
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,243 @@
+package tax1099
+
+import (
+	"testing"
+)
+
+func validSubmit1098Request() Submit1098Request {
+	return Submit1098Request{
+		TaxYear: "2024",
+		Items: []Item1098{{
+			PayerInfo: PayerInfo{
+				TinType:                TinTypeBusiness,
+				TaxIdentifer:           "12-3456789",
+				LastNameOrBusinessName: "Lender LLC",
+				Address:                "100 Main St",
+				City:                   "Austin",
+				State:                  "TX",
+				ZipCode:                "78701",
+				Country:                "US",
+				PhoneNumber:            "5125550100",
+			},
+			Forms: []Form1098{{
+				RecipientInfo: RecipientInfo{
+					TinType:                TinTypeIndividual,
+					TaxIdentifer:           "123456789",
+					FirstName:              "Jane",
+					LastNameOrBusinessName: "Borrower",
+					Address:                "200 Ranch Rd",
+					City:                   "Marfa",
+					State:                  "TX",
+					ZipCode:                "79843-1234",
+					PhoneNumber:            "4325550100",
+				},
+				TaxYear:          "2024",
+				MortgageInterest: MoneyFromCents(123456),
+				MortgageDate:     NewDate(2021, 4, 15),
+				IsAddressSame:    true,
+			}},
+		}},
+	}
+}
+
+func Test_Submit1098Request_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *Submit1098Request)
+		want   []ValidationError
+	}{
+		{
+			name:   "valid request",
+			modify: func(r *Submit1098Request) {},
+		},
+		{
+			name:   "request tax year taken from the forms",
+			modify: func(r *Submit1098Request) { r.TaxYear = "" },
+		},
+		{
+			name: "no tax year anywhere",
+			modify: func(r *Submit1098Request) {
+				r.TaxYear = ""
+				r.Items[0].Forms[0].TaxYear = ""
+			},
+			want: []ValidationError{{Field: "taxYear", Source: ""}},
+		},
+		{
+			name: "forms disagree and the request has no tax year",
+			modify: func(r *Submit1098Request) {
+				r.TaxYear = ""
+				other := r.Items[0].Forms[0]
+				other.TaxYear = "2023"
+				r.Items[0].Forms = append(r.Items[0].Forms, other)
+			},
+			want: []ValidationError{{Field: "taxYear", Source: "items[0].forms[1]"}},
+		},
+		{
+			name:   "form tax year disagrees with the request",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].TaxYear = "2023" },
+			want:   []ValidationError{{Field: "taxYear", Source: "items[0].forms[0]"}},
+		},
+		{
+			name: "missing payer fields",
+			modify: func(r *Submit1098Request) {
+				r.Items[0].PayerInfo.LastNameOrBusinessName = ""
+				r.Items[0].PayerInfo.PhoneNumber = ""
+			},
+			want: []ValidationError{
+				{Field: "lastNameOrBusinessName", Source: "items[0].payerInfo"},
+				{Field: "phone", Source: "items[0].payerInfo"},
+			},
+		},
+		{
+			name:   "EIN layout for an individual",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].RecipientInfo.TaxIdentifer = "12-3456789" },
+			want:   []ValidationError{{Field: "recipientTin", Source: "items[0].forms[0].recipientInfo"}},
+		},
+		{
+			name:   "short payer TIN",
+			modify: func(r *Submit1098Request) { r.Items[0].PayerInfo.TaxIdentifer = "1234567" },
+			want:   []ValidationError{{Field: "payerTin", Source: "items[0].payerInfo"}},
+		},
+		{
+			name:   "SSN in an area never issued",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].RecipientInfo.TaxIdentifer = "666-12-3456" },
+			want:   []ValidationError{{Field: "recipientTin", Source: "items[0].forms[0].recipientInfo"}},
+		},
+		{
+			name:   "unassigned EIN prefix",
+			modify: func(r *Submit1098Request) { r.Items[0].PayerInfo.TaxIdentifer = "07-1234567" },
+			want:   []ValidationError{{Field: "payerTin", Source: "items[0].payerInfo"}},
+		},
+		{
+			name:   "unknown TIN type",
+			modify: func(r *Submit1098Request) { r.Items[0].PayerInfo.TinType = "Trust" },
+			want:   []ValidationError{{Field: "tinType", Source: "items[0].payerInfo"}},
+		},
+		{
+			name: "bad state and ZIP",
+			modify: func(r *Submit1098Request) {
+				r.Items[0].Forms[0].RecipientInfo.State = "Texas"
+				r.Items[0].Forms[0].RecipientInfo.ZipCode = "7984"
+			},
+			want: []ValidationError{
+				{Field: "state", Source: "items[0].forms[0].recipientInfo"},
+				{Field: "zipCode", Source: "items[0].forms[0].recipientInfo"},
+			},
+		},
+		{
+			name: "foreign address skips state and ZIP checks",
+			modify: func(r *Submit1098Request) {
+				r.Items[0].Forms[0].RecipientInfo.Country = "MX"
+				r.Items[0].Forms[0].RecipientInfo.State = "Chihuahua"
+				r.Items[0].Forms[0].RecipientInfo.ZipCode = "31000"
+			},
+		},
+		{
+			name:   "negative amount",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].OverpaidInterest = MoneyFromCents(-100) },
+			want:   []ValidationError{{Field: "overpaidInterest", Source: "items[0].forms[0]"}},
+		},
+		{
+			name:   "impossible mortgage date",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].MortgageDate = NewDate(2021, 2, 30) },
+			want:   []ValidationError{{Field: "mortgageDate", Source: "items[0].forms[0]"}},
+		},
+		{
+			name:   "no mortgage date",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].MortgageDate = Date{} },
+		},
+		{
+			name:   "property address with isAddressSame",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].PropertyAddress = "300 Other Rd" },
+			want:   []ValidationError{{Field: "propertyAddress", Source: "items[0].forms[0]"}},
+		},
+		{
+			name:   "no property address or description without isAddressSame",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms[0].IsAddressSame = false },
+			want:   []ValidationError{{Field: "propertyAddress", Source: "items[0].forms[0]"}},
+		},
+		{
+			name: "property description without isAddressSame",
+			modify: func(r *Submit1098Request) {
+				r.Items[0].Forms[0].IsAddressSame = false
+				r.Items[0].Forms[0].PropertyDescription = "Lot 12, Block 4"
+			},
+		},
+		{
+			name:   "item without forms",
+			modify: func(r *Submit1098Request) { r.Items[0].Forms = nil },
+			want:   []ValidationError{{Field: "forms", Source: "items[0]"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := validSubmit1098Request()
+			tt.modify(&request)
+
+			got := request.Validate()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Validate() = %+v, want %d errors", got, len(tt.want))
+			}
+
+			for i := range tt.want {
+				if got[i].Field != tt.want[i].Field || got[i].Source != tt.want[i].Source {
+					t.Errorf("Validate()[%d] = %+v, want field %q at %q", i, got[i], tt.want[i].Field, tt.want[i].Source)
+				}
+				if got[i].Message == "" {
+					t.Errorf("Validate()[%d] has no message", i)
+				}
+			}
+		})
+	}
+}
+
+func Test_Form1098_Validate(t *testing.T) {
+	form := validSubmit1098Request().Items[0].Forms[0]
+	form.RecipientInfo.City = ""
+
+	got := form.Validate()
+	if len(got) != 1 || got[0].Field != "city" || got[0].Source != "recipientInfo" {
+		t.Errorf("Validate() = %+v, want one city error at recipientInfo", got)
+	}
+}
+
+func Test_Form1099C_Validate(t *testing.T) {
+	recipient := validSubmit1098Request().Items[0].Forms[0].RecipientInfo
+
+	tests := []struct {
+		name      string
+		eventCode EventCode
+		wantErr   bool
+	}{
+		{name: "code A", eventCode: EventCodeBankruptcy},
+		{name: "code H", eventCode: EventCodeOtherDischarge},
+		{name: "missing code", eventCode: "", wantErr: true},
+		{name: "code I", eventCode: "I", wantErr: true},
+		{name: "lower case code", eventCode: "f", wantErr: true},
+		{name: "two letters", eventCode: "AB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := Form1099C{RecipientInfo: recipient, TaxYear: "2024", DebtDischarged: MoneyFromCents(100000), EventCode: tt.eventCode}
+
+			got := form.Validate()
+			if !tt.wantErr && len(got) != 0 {
+				t.Errorf("Validate() = %+v, want none", got)
+			}
+			if tt.wantErr && (len(got) != 1 || got[0].Field != "identifiableEventCode") {
+				t.Errorf("Validate() = %+v, want one identifiableEventCode error", got)
+			}
+		})
+	}
+
+	request := Submit1099CRequest{
+		TaxYear: "2024",
+		Items: []Item1099C{{
+			PayerInfo: validSubmit1098Request().Items[0].PayerInfo,
+			Forms:     []Form1099C{{RecipientInfo: recipient, TaxYear: "2024", EventCode: "Z"}},
+		}},
+	}
+	if got := request.Validate(); len(got) != 1 || got[0].Source != "items[0].forms[0]" {
+		t.Errorf("Submit1099CRequest.Validate() = %+v, want the event code error at items[0].forms[0]", got)
+	}
+}